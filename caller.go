@@ -0,0 +1,273 @@
+package glog
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ====================================================================================================
+// CallerPathMode
+// ====================================================================================================
+// 輸出檔案資訊時，檔案路徑的呈現方式
+type CallerPathMode byte
+
+const (
+	// 完整路徑: /home/ci/go/src/github.com/j32u4ukh/glog/example/internal/worker.go
+	PathFull CallerPathMode = iota
+	// 相對於 module 根目錄的路徑: example/internal/worker.go
+	// 不屬於主 module 的檔案，則以套件路徑表示: net/http/server.go
+	PathRelative
+	// 套件名稱與檔名: internal/worker.go
+	PathPackage
+	// 僅檔名: worker.go
+	PathBase
+)
+
+func (m CallerPathMode) String() string {
+	switch m {
+	case PathFull:
+		return "PathFull"
+	case PathRelative:
+		return "PathRelative"
+	case PathPackage:
+		return "PathPackage"
+	case PathBase:
+		return "PathBase"
+	default:
+		return "Unknown"
+	}
+}
+
+// 主 module 的路徑，用於計算相對於 module 根目錄的路徑
+var mainModule string
+var mainModuleOnce sync.Once
+
+func getMainModule() string {
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModule = info.Main.Path
+		}
+	})
+	return mainModule
+}
+
+// 各目錄所屬主 module 的根目錄，不屬於主 module 時為空字串
+var moduleRoots sync.Map
+
+// 由 dir 往上尋找 go.mod，若為主 module 則回傳其所在目錄，否則回傳空字串
+func getModuleRoot(dir string) string {
+	// 以 -trimpath 建置時不是絕對路徑，不應在當前目錄下尋找
+	if !filepath.IsAbs(dir) {
+		return ""
+	}
+
+	if root, ok := moduleRoots.Load(dir); ok {
+		return root.(string)
+	}
+
+	root := ""
+	module := getMainModule()
+
+	for current := dir; module != ""; current = path.Dir(current) {
+		if data, err := os.ReadFile(path.Join(current, "go.mod")); err == nil {
+			if modulePath(data) == module {
+				root = current
+			}
+			break
+		}
+
+		if current == path.Dir(current) {
+			break
+		}
+	}
+
+	moduleRoots.Store(dir, root)
+	return root
+}
+
+// 讀取 go.mod 中的 module 路徑
+func modulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
+
+// 呼叫端函式的資訊，由 runtime.FuncForPC(pc).Name() 解析而來
+// 以 github.com/j32u4ukh/glog/example/internal.(*Worker).Debug 為例
+type callerInfo struct {
+	// 完整套件路徑: github.com/j32u4ukh/glog/example/internal
+	pkgPath string
+	// 套件名稱: internal
+	pkg string
+	// 方法的接收者型別，一般函式則為空字串: Worker
+	typ string
+	// 函式名稱，閉包會保留外層函式名稱，例如 main.func1: Debug
+	function string
+}
+
+// 解析結果依 PC 快取，同一個呼叫位置只需解析一次
+var callerCache sync.Map
+
+func getCallerInfo(pc uintptr) *callerInfo {
+	if info, ok := callerCache.Load(pc); ok {
+		return info.(*callerInfo)
+	}
+
+	fn := runtime.FuncForPC(pc)
+	var info *callerInfo
+
+	if fn == nil {
+		info = &callerInfo{}
+	} else {
+		info = parseFuncName(fn.Name())
+	}
+
+	callerCache.Store(pc, info)
+	return info
+}
+
+// 輸出用的標籤，例如 [internal] Worker.Debug
+func (c *callerInfo) label() string {
+	if c.typ == "" {
+		return fmt.Sprintf("[%s] %s", c.pkg, c.function)
+	}
+	return fmt.Sprintf("[%s] %s.%s", c.pkg, c.typ, c.function)
+}
+
+// 依 mode 呈現 file 的路徑
+func (c *callerInfo) filePath(file string, mode CallerPathMode) string {
+	switch mode {
+	case PathRelative:
+		// 由 file 所在目錄找到主 module 的根目錄，例如 example/cmd/demo1/main.go
+		if root := getModuleRoot(path.Dir(file)); root != "" {
+			return strings.TrimPrefix(file, root+"/")
+		}
+
+		base := path.Base(file)
+		module := getMainModule()
+
+		// 以 -trimpath 建置時，file 以 module 路徑開頭
+		if module != "" && strings.HasPrefix(file, module+"/") {
+			return strings.TrimPrefix(file, module+"/")
+		}
+
+		// 找不到 go.mod 時(例如在其他機器上執行)，改由套件路徑推算
+		// main 套件無法由套件路徑得知所在目錄，僅輸出檔名
+		if c.pkgPath == "" || c.pkgPath == "main" {
+			return base
+		} else if module != "" && c.pkgPath == module {
+			return base
+		} else if module != "" && strings.HasPrefix(c.pkgPath, module+"/") {
+			return path.Join(strings.TrimPrefix(c.pkgPath, module+"/"), base)
+		}
+
+		return path.Join(c.pkgPath, base)
+	case PathPackage:
+		return path.Join(path.Base(path.Dir(file)), path.Base(file))
+	case PathBase:
+		return path.Base(file)
+	default:
+		return file
+	}
+}
+
+// 解析 runtime 提供的函式名稱，可處理以下形式:
+// pkg.F, pkg.T.M, pkg.(*T).M, pkg.F.func1, pkg.(*T).M.func1.2, pkg.F[...], pkg.(*T[...]).M
+// 以及套件路徑中含有 '.' 的情形，例如 github.com/a/b.F、gopkg.in/yaml%2ev3.F
+func parseFuncName(name string) *callerInfo {
+	info := &callerInfo{}
+
+	// 泛型的型別參數以 [...] 表示，先行移除，避免干擾後續解析
+	name = removeTypeParams(name)
+
+	// 套件路徑的最後一段之後，第一個 '.' 為套件路徑與函式名稱的分界
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+
+	if dot < 0 {
+		info.pkgPath = name
+		info.function = name
+	} else {
+		info.pkgPath = name[:slash+1+dot]
+		info.function = name[slash+1+dot+1:]
+	}
+
+	// runtime 會將套件路徑最後一段中的 '.' 轉義為 %2e
+	info.pkgPath = strings.ReplaceAll(info.pkgPath, "%2e", ".")
+	info.pkg = info.pkgPath[strings.LastIndex(info.pkgPath, "/")+1:]
+
+	if strings.HasPrefix(info.function, "(") {
+		// 指標接收者: (*T).M
+		end := strings.Index(info.function, ")")
+
+		if end > 0 {
+			info.typ = strings.TrimPrefix(info.function[1:end], "*")
+			info.function = strings.TrimPrefix(info.function[end+1:], ".")
+		}
+	} else if parts := strings.SplitN(info.function, ".", 3); len(parts) >= 2 && !isClosureName(parts[1]) {
+		// 值接收者: T.M，需與閉包 F.func1 區分
+		info.typ = parts[0]
+		info.function = strings.Join(parts[1:], ".")
+	}
+
+	return info
+}
+
+// 移除名稱中以 [...] 表示的泛型型別參數
+func removeTypeParams(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+
+	var builder strings.Builder
+	depth := 0
+
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// 是否為編譯器產生的閉包或包裝函式名稱，例如 func1、1、gowrap1、deferwrap1
+func isClosureName(name string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseFuncName(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkgPath  string
+		pkg      string
+		typ      string
+		function string
+	}{
+		{"main.main.func1", "main", "main", "", "main.func1"},
+		{"pkg.F[...]", "pkg", "pkg", "", "F"},
+		{"pkg.(*T).M", "pkg", "pkg", "T", "M"},
+		{"pkg.(*T[...]).M", "pkg", "pkg", "T", "M"},
+		{"pkg.T.M.func1", "pkg", "pkg", "T", "M.func1"},
+		{"pkg.(*T).M.func1.2", "pkg", "pkg", "T", "M.func1.2"},
+		{"github.com/a/b.F", "github.com/a/b", "b", "", "F"},
+		{"github.com/a/b.(*T).M", "github.com/a/b", "b", "T", "M"},
+		{"gopkg.in/yaml%2ev3.F", "gopkg.in/yaml.v3", "yaml.v3", "", "F"},
+		{"pkg.init.0", "pkg", "pkg", "", "init.0"},
+		{"main.main.gowrap1", "main", "main", "", "main.gowrap1"},
+		{"pkg.F.deferwrap1", "pkg", "pkg", "", "F.deferwrap1"},
+		{"pkg.(*T).M.deferwrap2", "pkg", "pkg", "T", "M.deferwrap2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := parseFuncName(tt.name)
+
+			if info.pkgPath != tt.pkgPath || info.pkg != tt.pkg || info.typ != tt.typ || info.function != tt.function {
+				t.Errorf("parseFuncName(%q) = {pkgPath: %q, pkg: %q, typ: %q, function: %q}, want {pkgPath: %q, pkg: %q, typ: %q, function: %q}",
+					tt.name, info.pkgPath, info.pkg, info.typ, info.function, tt.pkgPath, tt.pkg, tt.typ, tt.function)
+			}
+		})
+	}
+}
+
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+// 以 WithCallerSkip 或 AddCallerSkip 略過自身的包裝型別
+type skipWrapper struct {
+	logger *Logger
+}
+
+func (w *skipWrapper) Warn(message string) {
+	w.logger.Warn(message)
+}
+
+// 以 Helper 標記自身的包裝型別
+type helperWrapper struct {
+	logger *Logger
+}
+
+func (w *helperWrapper) Warn(message string) {
+	w.logger.Helper()
+	w.logger.Warn(message)
+}
+
+// 各種輸出方式皆需回報實際的呼叫端，回傳值為預期的行數
+func TestCallerLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger) int
+	}{
+		{"direct", func(l *Logger) int {
+			line := callerLine() + 1
+			l.Warn("message")
+			return line
+		}},
+		{"WithCallerSkip", func(l *Logger) int {
+			w := &skipWrapper{logger: l.WithCallerSkip(1)}
+			line := callerLine() + 1
+			w.Warn("message")
+			return line
+		}},
+		{"direct after WithCallerSkip", func(l *Logger) int {
+			l.WithCallerSkip(1)
+			line := callerLine() + 1
+			l.Warn("message")
+			return line
+		}},
+		{"AddCallerSkip applied twice", func(l *Logger) int {
+			l.SetOptions(AddCallerSkip(1), AddCallerSkip(1))
+			w := &skipWrapper{logger: l}
+			line := callerLine() + 1
+			w.Warn("message")
+			return line
+		}},
+		{"Helper", func(l *Logger) int {
+			w := &helperWrapper{logger: l}
+			line := callerLine() + 1
+			w.Warn("message")
+			return line
+		}},
+		{"Logout", func(l *Logger) int {
+			line := callerLine() + 1
+			l.Logout(WarnLevel, "message")
+			return line
+		}},
+		{"WarnCtx", func(l *Logger) int {
+			line := callerLine() + 1
+			l.WarnCtx(context.Background(), "message")
+			return line
+		}},
+		{"Err", func(l *Logger) int {
+			line := callerLine() + 1
+			l.Err(nil, "message")
+			return line
+		}},
+		{"log.Printf", func(l *Logger) int {
+			restore := RedirectStdLog(l, WarnLevel)
+			defer restore()
+			line := callerLine() + 1
+			log.Printf("message")
+			return line
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			l := newLogger("caller", DebugLevel)
+			l.SetOptions(ConsoleWriterOption(buffer), CallerPathOption(PathBase))
+			want := fmt.Sprintf("caller_test.go:%d", tt.log(l))
+
+			if output := buffer.String(); !strings.Contains(output, want) {
+				t.Errorf("output = %q, want location %s", output, want)
+			}
+		})
+	}
+}
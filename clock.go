@@ -0,0 +1,71 @@
+package glog
+
+import (
+	"strconv"
+	"time"
+)
+
+// 特殊的時間輸出格式，其餘格式皆視為 time 的 layout
+const (
+	// RFC3339 精確到毫秒、微秒、奈秒，小數位數固定
+	TimeRFC3339Milli string = "2006-01-02T15:04:05.000Z07:00"
+	TimeRFC3339Micro string = "2006-01-02T15:04:05.000000Z07:00"
+	TimeRFC3339Nano  string = "2006-01-02T15:04:05.000000000Z07:00"
+	// Unix 時間戳，單位分別為秒、毫秒、奈秒
+	TimeUnix      string = "unix"
+	TimeUnixMilli string = "unixmilli"
+	TimeUnixNano  string = "unixnano"
+)
+
+// 時間來源，預設為 time.Now，測試時可替換為固定或可控的時間
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// 以函式實作 Clock
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// 設置時間來源，設為 nil 則還原為 time.Now
+func (l *Logger) SetClock(clock Clock) {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	l.clock = clock
+
+	// 開發用 Console 格式的相對時間，改以新的時間來源為起點
+	if l.pretty != nil {
+		l.pretty.reset(l.getTime())
+	}
+}
+
+// 設置時間輸出格式，可為 time 的 layout(例如 time.RFC3339、TimeRFC3339Milli)，
+// 或 TimeUnix, TimeUnixMilli, TimeUnixNano；空字串則還原為 DISPLAYTIME
+func (l *Logger) SetTimeFormat(format string) {
+	if format == "" {
+		format = DISPLAYTIME
+	}
+	l.timeFormat = format
+}
+
+func (l *Logger) formatTime(t time.Time) string {
+	switch l.timeFormat {
+	case TimeUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case TimeUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	default:
+		return t.Format(l.timeFormat)
+	}
+}
@@ -0,0 +1,148 @@
+package glog
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ====================================================================================================
+// ColorMode
+// ====================================================================================================
+// Console 輸出是否上色
+type ColorMode byte
+
+const (
+	// 輸出位置為終端機，且環境變數 NO_COLOR 未設置或為空字串時上色
+	ColorAuto ColorMode = iota
+	// 總是上色
+	ColorAlways
+	// 不上色
+	ColorNever
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAuto:
+		return "ColorAuto"
+	case ColorAlways:
+		return "ColorAlways"
+	case ColorNever:
+		return "ColorNever"
+	default:
+		return "Unknown"
+	}
+}
+
+// Console 輸出所用的顏色，值為 ANSI SGR 參數，例如 "31"(紅)、"1;33"(粗體黃)，空字串表示不上色
+type Palette struct {
+	Levels map[LogLevel]string
+	// 呼叫端標籤
+	Label string
+	// 欄位的 key
+	Key string
+}
+
+// 預設顏色: Debug 青色、Info 綠色、Warn 黃色、Error 粗體紅色，標籤與 key 不上色
+func DefaultPalette() *Palette {
+	p := &Palette{
+		Levels: map[LogLevel]string{
+			DebugLevel: "36",
+			InfoLevel:  "32",
+			WarnLevel:  "33",
+			ErrorLevel: "1;31",
+		},
+		Label: "",
+		Key:   "",
+	}
+	return p
+}
+
+// 以 code 為 text 上色，palette 為 nil 或 code 為空字串時不上色
+func (p *Palette) paint(code string, text string) string {
+	if p == nil || code == "" {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, text)
+}
+
+func (p *Palette) paintLevel(level LogLevel) string {
+	if p == nil {
+		return level.String()
+	}
+	return p.paint(p.levelCode(level), level.String())
+}
+
+func (p *Palette) levelCode(level LogLevel) string {
+	if p == nil {
+		return ""
+	}
+	return p.Levels[level]
+}
+
+func (p *Palette) paletteLabel() string {
+	if p == nil {
+		return ""
+	}
+	return p.Label
+}
+
+func (p *Palette) paletteKey() string {
+	if p == nil {
+		return ""
+	}
+	return p.Key
+}
+
+// 設置 Console 輸出是否上色
+func (l *Logger) SetColorMode(mode ColorMode) {
+	l.colorMode = mode
+	l.updateColors()
+}
+
+// 依各個 Level 的輸出位置，重新決定是否上色
+func (l *Logger) updateColors() {
+	for _, level := range []LogLevel{DebugLevel, InfoLevel, WarnLevel, ErrorLevel} {
+		l.colors[level] = resolveColor(l.colorMode, l.consoles[level])
+	}
+}
+
+// 設置 Console 輸出所用的顏色
+func (l *Logger) SetPalette(palette *Palette) {
+	if palette == nil {
+		palette = DefaultPalette()
+	}
+	l.palette = palette
+}
+
+func resolveColor(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		// 依 NO_COLOR 的慣例，設為空字串時視同未設置
+		if os.Getenv("NO_COLOR") != "" {
+			return false
+		}
+		return isTerminal(w)
+	}
+}
+
+// 是否為終端機(字元裝置)，輸出被導向檔案、管線或非 *os.File 的 io.Writer 時為 false
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+
+	if !ok || file == nil {
+		return false
+	}
+
+	stat, err := file.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
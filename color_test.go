@@ -0,0 +1,39 @@
+package glog
+
+import (
+	"os"
+	"testing"
+)
+
+func TestResolveColorNoColor(t *testing.T) {
+	// /dev/null 為字元裝置，視同終端機
+	device, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+
+	if err != nil {
+		t.Skip(err)
+	}
+
+	defer device.Close()
+
+	if !isTerminal(device) {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+
+	tests := []struct {
+		noColor string
+		want    bool
+	}{
+		{"", true},
+		{"1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run("NO_COLOR="+tt.noColor, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			if got := resolveColor(ColorAuto, device); got != tt.want {
+				t.Errorf("resolveColor(ColorAuto) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package glog
+
+import (
+	"io"
+	"os"
+)
+
+// ====================================================================================================
+// ConsoleMode
+// ====================================================================================================
+// Console 輸出位置的預設組合
+type ConsoleMode byte
+
+const (
+	// 全部輸出到 stdout
+	ConsoleStdout ConsoleMode = iota
+	// 全部輸出到 stderr
+	ConsoleStderr
+	// Warn 以上輸出到 stderr，其餘輸出到 stdout
+	ConsoleSplit
+)
+
+func (m ConsoleMode) String() string {
+	switch m {
+	case ConsoleStdout:
+		return "ConsoleStdout"
+	case ConsoleStderr:
+		return "ConsoleStderr"
+	case ConsoleSplit:
+		return "ConsoleSplit"
+	default:
+		return "Unknown"
+	}
+}
+
+// 依預設組合設置 Console 輸出位置
+func (l *Logger) SetConsole(mode ConsoleMode) {
+	switch mode {
+	case ConsoleStderr:
+		l.SetConsoleWriter(os.Stderr)
+	case ConsoleSplit:
+		l.SetConsoleWriter(os.Stdout, DebugLevel, InfoLevel)
+		l.SetConsoleWriter(os.Stderr, WarnLevel, ErrorLevel)
+	default:
+		l.SetConsoleWriter(os.Stdout)
+	}
+}
+
+// 設置 levels 輸出到 Console 的位置，未給定 levels 時套用到所有等級
+func (l *Logger) SetConsoleWriter(w io.Writer, levels ...LogLevel) {
+	if len(levels) == 0 {
+		levels = []LogLevel{DebugLevel, InfoLevel, WarnLevel, ErrorLevel}
+	}
+
+	l.consoleMu.Lock()
+	for _, level := range levels {
+		l.consoles[level] = w
+	}
+	l.consoleMu.Unlock()
+
+	l.updateColors()
+}
+
+func (l *Logger) writeConsole(level LogLevel, output string) {
+	l.consoleMu.Lock()
+	defer l.consoleMu.Unlock()
+
+	if w, ok := l.consoles[level]; ok && w != nil {
+		io.WriteString(w, output)
+	}
+}
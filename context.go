@@ -0,0 +1,102 @@
+package glog
+
+import (
+	"context"
+	"sync"
+)
+
+// 作為 context 中存放欄位的 key，使用未導出的型別以避免與其他套件衝突
+type contextKey struct{}
+
+// 從 context 中取出額外欄位的函式，例如 OpenTelemetry 的 trace_id 與 span_id
+// glog 本身不依賴 OpenTelemetry，由使用端註冊，例如:
+//
+//	glog.RegisterContextExtractor(func(ctx context.Context) []glog.Field {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return nil
+//		}
+//		return []glog.Field{
+//			glog.NewField("trace_id", sc.TraceID().String()),
+//			glog.NewField("span_id", sc.SpanID().String()),
+//		}
+//	})
+type ContextExtractor func(ctx context.Context) []Field
+
+var extractors []ContextExtractor
+var extractorMu sync.RWMutex
+
+// 註冊 ContextExtractor，所有 Logger 的 *Ctx 系列函式皆會套用
+func RegisterContextExtractor(extractor ContextExtractor) {
+	extractorMu.Lock()
+	defer extractorMu.Unlock()
+	extractors = append(extractors, extractor)
+}
+
+// 將欄位加入 context，之後透過 *Ctx 系列函式輸出時，會自動附加這些欄位
+// 原本 context 中已有的欄位會被保留，新的欄位接在其後
+func WithContext(ctx context.Context, fields ...Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	olds := FromContext(ctx)
+	news := make([]Field, 0, len(olds)+len(fields))
+	news = append(news, olds...)
+	news = append(news, fields...)
+	return context.WithValue(ctx, contextKey{}, news)
+}
+
+// 取得 context 中存放的欄位
+func FromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	if fields, ok := ctx.Value(contextKey{}).([]Field); ok {
+		return fields
+	}
+
+	return nil
+}
+
+// 取得 context 中存放的欄位，以及各個 ContextExtractor 取出的欄位
+func contextFields(ctx context.Context) []Field {
+	fields := FromContext(ctx)
+
+	if ctx == nil {
+		return fields
+	}
+
+	extractorMu.RLock()
+	defer extractorMu.RUnlock()
+
+	if len(extractors) == 0 {
+		return fields
+	}
+
+	// 複製一份，避免 append 改動到 context 中的欄位
+	fields = append([]Field{}, fields...)
+
+	for _, extractor := range extractors {
+		fields = append(fields, extractor(ctx)...)
+	}
+
+	return fields
+}
+
+func (l *Logger) DebugCtx(ctx context.Context, message string, a ...any) {
+	l.logout(2, &entry{level: DebugLevel, message: message, args: a, fields: contextFields(ctx)})
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, message string, a ...any) {
+	l.logout(2, &entry{level: InfoLevel, message: message, args: a, fields: contextFields(ctx)})
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, message string, a ...any) {
+	l.logout(2, &entry{level: WarnLevel, message: message, args: a, fields: contextFields(ctx)})
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, message string, a ...any) {
+	l.logout(2, &entry{level: ErrorLevel, message: message, args: a, fields: contextFields(ctx)})
+}
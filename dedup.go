@@ -0,0 +1,127 @@
+package glog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 等級、呼叫端與訊息皆相同，視為重複的訊息
+type dedupKey struct {
+	level   LogLevel
+	pc      uintptr
+	message string
+	fields  string
+}
+
+// 類似 syslog，將連續重複的訊息合併為 "last message repeated N times"
+// 在不同的訊息到來，或是超過 timeout 時輸出重複次數，尚未輸出的重複次數會在 Close 時輸出
+type deduper struct {
+	logger  *Logger
+	timeout time.Duration
+	// 上一筆輸出的訊息
+	last    dedupKey
+	hasLast bool
+	// 上一筆訊息之後，被略過的重複次數
+	repeated uint64
+	// 第一次被略過的時間
+	since time.Time
+	// 第一次被略過後，經過 timeout 時輸出重複次數
+	timer *time.Timer
+	// 每次輸出重複次數後遞增，用於忽略已過期的 timer
+	generation uint64
+	mu         sync.Mutex
+}
+
+// 設置合併連續重複的訊息，重複次數最晚在 timeout 後輸出，timeout 小於等於 0 時取消合併
+func (l *Logger) SetDedup(timeout time.Duration) {
+	if l.deduper != nil {
+		if report := l.deduper.flush(); report != nil {
+			l.logout(0, report)
+		}
+	}
+
+	if timeout <= 0 {
+		l.deduper = nil
+		return
+	}
+
+	l.deduper = &deduper{
+		logger:  l,
+		timeout: timeout,
+	}
+}
+
+// 檢查該筆訊息是否輸出，若上一筆訊息有被略過的重複，一併回傳重複次數的統計訊息
+func (d *deduper) check(key dedupKey, now time.Time) (bool, *entry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.hasLast && key == d.last {
+		var report *entry
+
+		// 超過 timeout 時先輸出目前的重複次數，該筆訊息重新開始計算
+		if d.repeated > 0 && now.Sub(d.since) >= d.timeout {
+			report = d.report()
+		}
+
+		if d.repeated == 0 {
+			d.since = now
+			generation := d.generation
+			d.timer = time.AfterFunc(d.timeout, func() { d.onTimeout(generation) })
+		}
+
+		d.repeated++
+		return false, report
+	}
+
+	report := d.report()
+	d.last = key
+	d.hasLast = true
+	return true, report
+}
+
+// 取出尚未輸出的重複次數
+func (d *deduper) flush() *entry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.report()
+}
+
+// 經過 timeout 後輸出重複次數，若期間已因其他訊息、Close 等原因輸出過，則忽略
+func (d *deduper) onTimeout(generation uint64) {
+	d.mu.Lock()
+
+	if generation != d.generation {
+		d.mu.Unlock()
+		return
+	}
+
+	report := d.report()
+	d.mu.Unlock()
+
+	if report != nil {
+		d.logger.logout(0, report)
+	}
+}
+
+// 將重複次數轉為統計訊息並歸零，同時停止尚未觸發的 timer，呼叫前需持有 mu
+func (d *deduper) report() *entry {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	if d.repeated == 0 {
+		return nil
+	}
+
+	report := &entry{
+		level:   d.last.level,
+		message: fmt.Sprintf("last message repeated %d times", d.repeated),
+		summary: true,
+	}
+	d.repeated = 0
+	d.generation++
+	return report
+}
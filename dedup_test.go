@@ -0,0 +1,114 @@
+package glog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 可同時被多個 goroutine 寫入與讀取的 Buffer
+type lockedBuffer struct {
+	buffer bytes.Buffer
+	mu     sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+func TestDedupTimeout(t *testing.T) {
+	buffer := &lockedBuffer{}
+	l := newLogger("dedup", DebugLevel)
+	l.SetOptions(ConsoleWriterOption(buffer), DedupOption(20*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		l.Info("reconnecting")
+	}
+
+	deadline := time.Now().Add(time.Second)
+
+	for !strings.Contains(buffer.String(), "last message repeated 2 times") {
+		if time.Now().After(deadline) {
+			t.Fatalf("repeat count was not reported after the timeout, output: %q", buffer.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 已由 timer 輸出的重複次數，Close 時不應再次輸出
+	l.Close()
+
+	if n := strings.Count(buffer.String(), "last message repeated"); n != 1 {
+		t.Errorf("repeat count reported %d times, want 1, output: %q", n, buffer.String())
+	}
+}
+
+func TestDedupCloseStopsTimer(t *testing.T) {
+	buffer := &lockedBuffer{}
+	l := newLogger("dedup", DebugLevel)
+	l.SetOptions(ConsoleWriterOption(buffer), DedupOption(20*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		l.Info("reconnecting")
+	}
+
+	l.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	if n := strings.Count(buffer.String(), "last message repeated 2 times"); n != 1 {
+		t.Errorf("repeat count reported %d times, want 1, output: %q", n, buffer.String())
+	}
+}
+
+// 統計訊息輸出在下一筆 log 之前，時間不可晚於該筆 log
+func TestDedupSummaryTime(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	now := time.Unix(0, 0)
+	l := newLogger("dedup", DebugLevel)
+	l.SetOptions(
+		ConsoleWriterOption(buffer),
+		DedupOption(time.Hour),
+		TimeFormatOption(TimeUnix),
+		ClockOption(ClockFunc(func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		})),
+	)
+
+	for i := 0; i < 3; i++ {
+		l.Info("reconnecting")
+	}
+
+	l.Info("connected")
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3, output: %q", len(lines), buffer.String())
+	}
+
+	last := int64(0)
+
+	for _, line := range lines {
+		stamp, err := strconv.ParseInt(strings.Fields(line)[0], 10, 64)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stamp < last {
+			t.Errorf("timestamps out of order, output: %q", buffer.String())
+		}
+
+		last = stamp
+	}
+}
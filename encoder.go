@@ -0,0 +1,182 @@
+package glog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ====================================================================================================
+// ConsoleEncoder
+// ====================================================================================================
+// Console 輸出的格式
+type ConsoleEncoder byte
+
+const (
+	// 與檔案相同的格式
+	EncoderText ConsoleEncoder = iota
+	// 開發用格式: 欄位對齊、相對時間、簡短的呼叫端、欄位逐行列出、錯誤與堆疊醒目標示
+	EncoderPretty
+)
+
+func (e ConsoleEncoder) String() string {
+	switch e {
+	case EncoderText:
+		return "EncoderText"
+	case EncoderPretty:
+		return "EncoderPretty"
+	default:
+		return "Unknown"
+	}
+}
+
+// 設置 Console 輸出的格式，不影響輸出到檔案的格式
+func (l *Logger) SetConsoleEncoder(encoder ConsoleEncoder) {
+	switch encoder {
+	case EncoderPretty:
+		l.pretty = newPrettyEncoder(l.getTime())
+	default:
+		l.pretty = nil
+	}
+}
+
+// 一筆 log 在輸出前的各個部分
+type record struct {
+	now   time.Time
+	time  string
+	level LogLevel
+	// 呼叫端標籤，未取得呼叫端時為空字串
+	label   string
+	message string
+	fields  []Field
+	// 檔案與行數資訊，未輸出時為空字串
+	location string
+	// 檔名與行數，不受 FILEINFO, LINEINFO 影響，供開發用的 Console 格式使用
+	shortLocation string
+	stack         []string
+}
+
+// 組合成一行(含堆疊則為多行)文字，palette 為 nil 時不上色
+// 格式: 時間 等級 | 標籤 | 訊息 | 欄位 | 檔案:行數
+func (r *record) format(palette *Palette) string {
+	var builder strings.Builder
+	builder.WriteString(r.time)
+	builder.WriteString(" ")
+	builder.WriteString(palette.paintLevel(r.level))
+
+	if r.label != "" {
+		builder.WriteString(" | ")
+		builder.WriteString(palette.paint(palette.paletteLabel(), r.label))
+	}
+
+	builder.WriteString(" | ")
+	builder.WriteString(r.message)
+
+	if len(r.fields) > 0 {
+		builder.WriteString(" | ")
+
+		for i, field := range r.fields {
+			if i > 0 {
+				builder.WriteString(" ")
+			}
+
+			builder.WriteString(palette.paint(palette.paletteKey(), field.Key))
+			builder.WriteString("=")
+			builder.WriteString(field.valueString())
+		}
+	}
+
+	if r.location != "" {
+		builder.WriteString(" | ")
+		builder.WriteString(r.location)
+	}
+
+	builder.WriteString("\n")
+
+	for _, line := range r.stack {
+		builder.WriteString("\t")
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// 開發用的 Console 格式，例如:
+//
+//	+1.234s Warn  [internal] Worker.Run   worker.go:42  connection lost
+//	              retry: 3
+//	              error: dial tcp: connection refused
+type prettyEncoder struct {
+	// 相對時間的起點
+	start time.Time
+	// 目前為止最長的標籤與呼叫端，用於對齊
+	labelWidth    int
+	locationWidth int
+	mu            sync.Mutex
+}
+
+func newPrettyEncoder(start time.Time) *prettyEncoder {
+	p := &prettyEncoder{
+		start:         start,
+		labelWidth:    0,
+		locationWidth: 0,
+	}
+	return p
+}
+
+// 重新設置相對時間的起點
+func (p *prettyEncoder) reset(start time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.start = start
+}
+
+// 相對時間欄位的寬度，例如 +1234.567s
+const prettyElapsedWidth int = 10
+
+func (p *prettyEncoder) encode(r *record, palette *Palette) string {
+	p.mu.Lock()
+	if len(r.label) > p.labelWidth {
+		p.labelWidth = len(r.label)
+	}
+	if len(r.shortLocation) > p.locationWidth {
+		p.locationWidth = len(r.shortLocation)
+	}
+	labelWidth, locationWidth, start := p.labelWidth, p.locationWidth, p.start
+	p.mu.Unlock()
+
+	var builder strings.Builder
+	elapsed := fmt.Sprintf("+%.3fs", r.now.Sub(start).Seconds())
+	builder.WriteString(fmt.Sprintf("%-*s ", prettyElapsedWidth, elapsed))
+	builder.WriteString(palette.paintLevel(r.level))
+	builder.WriteString(" ")
+	builder.WriteString(palette.paint(palette.paletteLabel(), fmt.Sprintf("%-*s", labelWidth, r.label)))
+	builder.WriteString(" ")
+	builder.WriteString(fmt.Sprintf("%-*s", locationWidth, r.shortLocation))
+	builder.WriteString("  ")
+	builder.WriteString(r.message)
+	builder.WriteString("\n")
+
+	// 欄位與堆疊對齊到等級欄位之下
+	indent := strings.Repeat(" ", prettyElapsedWidth+1)
+
+	for _, field := range r.fields {
+		value := field.valueString()
+
+		if field.Key == "error" {
+			value = palette.paint(palette.levelCode(ErrorLevel), value)
+		}
+
+		builder.WriteString(fmt.Sprintf("%s%s: %s\n", indent, palette.paint(palette.paletteKey(), field.Key), value))
+	}
+
+	for _, line := range r.stack {
+		builder.WriteString(indent)
+		builder.WriteString(palette.paint("2", line))
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
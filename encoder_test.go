@@ -0,0 +1,35 @@
+package glog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 相對時間的起點需跟隨時間來源，不論 Option 的順序
+func TestPrettyEncoderClock(t *testing.T) {
+	clock := ClockFunc(func() time.Time { return time.Unix(0, 0) })
+
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"clock after pretty", []Option{PrettyConsoleOption(), ClockOption(clock)}},
+		{"clock before pretty", []Option{ClockOption(clock), PrettyConsoleOption()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			l := newLogger("pretty", DebugLevel)
+			l.SetOptions(ConsoleWriterOption(buffer))
+			l.SetOptions(tt.options...)
+			l.Info("hello")
+
+			if output := buffer.String(); !strings.HasPrefix(output, "+0.000s") {
+				t.Errorf("output = %q, want prefix %q", output, "+0.000s")
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package glog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// pkg/errors 所產生的錯誤，會實作此介面以提供堆疊
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// 以 Error 等級輸出錯誤，包含錯誤訊息、錯誤鏈，以及 pkg/errors 所記錄的堆疊(若有)
+func (l *Logger) Err(err error, message string, fields ...Field) {
+	e := &entry{
+		level:   ErrorLevel,
+		message: message,
+		fields:  append([]Field{}, fields...),
+	}
+
+	if err != nil {
+		e.fields = append(e.fields, NewField("error", err.Error()))
+
+		if chain := errorChain(err); len(chain) > 1 {
+			e.fields = append(e.fields, NewField("error_chain", strings.Join(chain, " -> ")))
+		}
+
+		e.stack = errorStack(err)
+	}
+
+	l.logout(2, e)
+}
+
+// 依序取得錯誤鏈中，每一層錯誤自身所增加的訊息
+func errorChain(err error) []string {
+	chain := []string{}
+	var next error
+	var text string
+
+	for err != nil {
+		next = unwrapError(err)
+		text = err.Error()
+
+		if next != nil {
+			text = strings.TrimSuffix(strings.TrimSuffix(text, next.Error()), ": ")
+		}
+
+		// pkg/errors 的 WithStack 不會改變訊息，無須重複記錄
+		if text != "" {
+			chain = append(chain, text)
+		}
+
+		err = next
+	}
+
+	return chain
+}
+
+// 取得錯誤鏈中最內層的堆疊，也就是最接近錯誤發生處的堆疊
+func errorStack(err error) []string {
+	var trace errors.StackTrace
+
+	for err != nil {
+		if tracer, ok := err.(stackTracer); ok {
+			trace = tracer.StackTrace()
+		}
+		err = unwrapError(err)
+	}
+
+	stack := make([]string, 0, 2*len(trace))
+
+	for _, frame := range trace {
+		// %+v 的格式為 "函式名稱\n\t檔案:行數"
+		lines := strings.SplitN(fmt.Sprintf("%+v", frame), "\n\t", 2)
+		stack = append(stack, lines[0])
+
+		if len(lines) == 2 {
+			stack = append(stack, "\t"+lines[1])
+		}
+	}
+
+	return stack
+}
+
+// 同時支援標準函式庫的 Unwrap 以及 pkg/errors 的 Cause
+func unwrapError(err error) error {
+	if next := errors.Unwrap(err); next != nil {
+		return next
+	}
+
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		return causer.Cause()
+	}
+
+	return nil
+}
+
+// 將多個錯誤合併為一個，沒有錯誤時回傳 nil
+type multiError []error
+
+func (m multiError) Error() string {
+	texts := make([]string, len(m))
+	for i, err := range m {
+		texts[i] = err.Error()
+	}
+	return strings.Join(texts, "; ")
+}
+
+func combineErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return multiError(errs)
+	}
+}
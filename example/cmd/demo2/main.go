@@ -10,6 +10,8 @@ import (
 )
 
 func main() {
+	// internal.Run 不會結束，收到中斷訊號時由 glog 寫出並關閉輸出檔
+	glog.HandleSignals()
 	logger := glog.SetLogger(0, "cmd-internal", glog.DebugLevel)
 	logger.SetFolder("../../log")
 	logger.SetOptions(glog.DefaultOption(false, false), glog.UtcOption(8))
@@ -13,18 +13,22 @@ type Worker struct {
 }
 
 func (w *Worker) Debug(format string, args ...any) {
+	w.logger.Helper()
 	w.logger.Debug(format, args...)
 }
 
 func (w *Worker) Info(format string, args ...any) {
+	w.logger.Helper()
 	w.logger.Info(format, args...)
 }
 
 func (w *Worker) Warn(format string, args ...any) {
+	w.logger.Helper()
 	w.logger.Warn(format, args...)
 }
 
 func (w *Worker) Error(format string, args ...any) {
+	w.logger.Helper()
 	w.logger.Error(format, args...)
 }
 
@@ -0,0 +1,37 @@
+package glog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 附加在 log 訊息之後的 key=value 欄位
+type Field struct {
+	Key   string
+	Value any
+}
+
+func NewField(key string, value any) Field {
+	f := Field{
+		Key:   key,
+		Value: value,
+	}
+	return f
+}
+
+func (f Field) String() string {
+	return fmt.Sprintf("%s=%s", f.Key, f.valueString())
+}
+
+func (f Field) valueString() string {
+	return fmt.Sprintf("%v", f.Value)
+}
+
+// 將多個欄位以空白分隔，組合成一個字串
+func formatFields(fields []Field) string {
+	texts := make([]string, len(fields))
+	for i, field := range fields {
+		texts[i] = field.String()
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,242 @@
+package glog
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// 過濾規則，各條件皆為空時不限制，有設置的條件需全部符合才算符合此規則
+type Filter struct {
+	// 呼叫端套件，可為套件名稱(即 [pkg] 中的 pkg)或完整套件路徑，支援 path.Match 的萬用字元
+	Package string
+	// 呼叫端函式，方法的形式為 Type.Func，支援 path.Match 的萬用字元，例如 "Worker.*"
+	Function string
+	// 訊息(不含欄位)的正規表示式
+	Message *regexp.Regexp
+	// 欄位 key 與其值(以 %v 格式化後比較)
+	FieldKey   string
+	FieldValue string
+	// true: 略過符合此規則的 log; false: 只輸出符合任一 include 規則的 log
+	Exclude bool
+}
+
+// 套件與其輸出等級
+type packageLevel struct {
+	pattern string
+	level   LogLevel
+}
+
+type filterSet struct {
+	// 依設定順序比對，第一個符合的套件決定輸出等級
+	levels []packageLevel
+	// 未符合任何套件時的輸出等級(即 "*")，未設置時使用 Logger 的等級
+	defaultLevel    LogLevel
+	hasDefaultLevel bool
+	includes        []Filter
+	excludes        []Filter
+	mu              sync.RWMutex
+}
+
+// 將字串轉為 LogLevel，不分大小寫，可用 debug, info, warn(warning), error
+func ParseLogLevel(text string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	default:
+		return DebugLevel, errors.Errorf("無法辨識的 LogLevel: %s", text)
+	}
+}
+
+// 新增過濾規則
+func (l *Logger) AddFilter(filter Filter) {
+	f := l.getFilters()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if filter.Exclude {
+		f.excludes = append(f.excludes, filter)
+	} else {
+		f.includes = append(f.includes, filter)
+	}
+}
+
+// 依呼叫端套件設置輸出等級，格式為以逗號分隔的 套件=等級，例如 "db=debug,*=info"
+// 套件可為套件名稱或完整套件路徑，支援 path.Match 的萬用字元，"*" 表示其餘套件
+// 重新設置時會取代原本的設定，傳入空字串則清除
+func (l *Logger) SetLevelSpec(spec string) error {
+	levels := []packageLevel{}
+	var defaultLevel LogLevel
+	hasDefaultLevel := false
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+
+		if item == "" {
+			continue
+		}
+
+		pattern, text, ok := strings.Cut(item, "=")
+
+		if !ok {
+			return errors.Errorf("格式錯誤，應為 套件=等級: %s", item)
+		}
+
+		level, err := ParseLogLevel(text)
+
+		if err != nil {
+			return err
+		}
+
+		pattern = strings.TrimSpace(pattern)
+
+		if pattern == "*" {
+			defaultLevel = level
+			hasDefaultLevel = true
+			continue
+		}
+
+		if _, err = path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "套件格式錯誤: %s", pattern)
+		}
+
+		levels = append(levels, packageLevel{pattern: pattern, level: level})
+	}
+
+	f := l.getFilters()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.levels = levels
+	f.defaultLevel = defaultLevel
+	f.hasDefaultLevel = hasDefaultLevel
+	return nil
+}
+
+func (l *Logger) getFilters() *filterSet {
+	if l.filters == nil {
+		l.filters = &filterSet{}
+	}
+	return l.filters
+}
+
+// 所有設定中最低的輸出等級，用於在取得呼叫端之前，先略過不可能輸出的 log
+func (l *Logger) minLevel() LogLevel {
+	if l.filters == nil {
+		return l.level
+	}
+
+	l.filters.mu.RLock()
+	defer l.filters.mu.RUnlock()
+	level := l.level
+
+	if l.filters.hasDefaultLevel {
+		level = l.filters.defaultLevel
+	}
+
+	for _, pl := range l.filters.levels {
+		if pl.level < level {
+			level = pl.level
+		}
+	}
+
+	return level
+}
+
+// 取得呼叫端套件的輸出等級
+func (f *filterSet) packageLevel(info *callerInfo, level LogLevel) LogLevel {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, pl := range f.levels {
+		if matchPackage(pl.pattern, info) {
+			return pl.level
+		}
+	}
+
+	if f.hasDefaultLevel {
+		return f.defaultLevel
+	}
+
+	return level
+}
+
+// 是否輸出: 不可符合任一 exclude 規則，且有 include 規則時，需符合其中之一
+func (f *filterSet) allow(info *callerInfo, message string, fields []Field) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, filter := range f.excludes {
+		if filter.match(info, message, fields) {
+			return false
+		}
+	}
+
+	if len(f.includes) == 0 {
+		return true
+	}
+
+	for _, filter := range f.includes {
+		if filter.match(info, message, fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (filter *Filter) match(info *callerInfo, message string, fields []Field) bool {
+	if filter.Package != "" && !matchPackage(filter.Package, info) {
+		return false
+	}
+
+	if filter.Function != "" {
+		function := info.function
+
+		if info.typ != "" {
+			function = fmt.Sprintf("%s.%s", info.typ, info.function)
+		}
+
+		if ok, _ := path.Match(filter.Function, function); !ok {
+			return false
+		}
+	}
+
+	if filter.Message != nil && !filter.Message.MatchString(message) {
+		return false
+	}
+
+	if filter.FieldKey != "" {
+		found := false
+
+		for _, field := range fields {
+			if field.Key == filter.FieldKey && fmt.Sprintf("%v", field.Value) == filter.FieldValue {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchPackage(pattern string, info *callerInfo) bool {
+	if ok, _ := path.Match(pattern, info.pkg); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, info.pkgPath)
+	return ok
+}
@@ -0,0 +1,44 @@
+package glog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 被過濾的 log 不應佔用抽樣次數與速率限制的額度
+func TestFilterBeforeSamplingAndRateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+	}{
+		{"rate limit", RateLimitOption(InfoLevel, 1, 1)},
+		{"sampling", SamplingOption(time.Minute, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			l := newLogger("filter", DebugLevel)
+			l.SetOptions(ConsoleWriterOption(buffer), tt.option)
+			l.AddFilter(Filter{Message: regexp.MustCompile("noise"), Exclude: true})
+
+			for i := 0; i < 5; i++ {
+				l.Info("noise %d", i)
+			}
+
+			l.Info("real message")
+			output := buffer.String()
+
+			if !strings.Contains(output, "real message") {
+				t.Errorf("real message was dropped, output: %q", output)
+			}
+
+			if strings.Contains(output, "noise") || strings.Contains(output, "glog:") {
+				t.Errorf("excluded entries were counted, output: %q", output)
+			}
+		})
+	}
+}
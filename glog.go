@@ -1,25 +1,27 @@
 package glog
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+
+	"github.com/pkg/errors"
 )
 
 var loggerMap map[byte]*Logger
-var exitChan chan os.Signal
+var loggerMu sync.RWMutex
 
 // TODO: v2.0.0 時，將建構子中的 callByStruct 移除
 // TODO: v2.1.0 時，換檔機制新增: 與開始執行時間點無關，每日零點起算，間隔數小時(0~5: 0; 6~11: 6; 12~17: 12; 18~23: 18)
 func init() {
 	loggerMap = make(map[byte]*Logger)
-	exitChan = make(chan os.Signal)
-	signal.Notify(exitChan, os.Interrupt, syscall.SIGTERM)
-	go exitHandle()
 }
 
 func SetLogger(idx byte, loggerName string, level LogLevel, options ...Option) *Logger {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
 	var logger *Logger
 	var ok bool
 	if logger, ok = loggerMap[idx]; ok {
@@ -31,22 +33,88 @@ func SetLogger(idx byte, loggerName string, level LogLevel, options ...Option) *
 }
 
 func GetLogger(idx byte) *Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
 	if logger, ok := loggerMap[idx]; ok {
 		return logger
 	}
 	return nil
 }
 
+// 將 Logger 從管理中移除，並關閉其輸出檔
+// 移除後，相同的 idx 可再透過 SetLogger 建立新的 Logger
+func RemoveLogger(idx byte) error {
+	loggerMu.Lock()
+	logger, ok := loggerMap[idx]
+	delete(loggerMap, idx)
+	loggerMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return logger.Close()
+}
+
 func Flush() {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
 	for _, logger := range loggerMap {
 		logger.Flush()
 	}
-	fmt.Println("glog.Flush | 完成寫出")
+	internalLog("flush", NewField("loggers", len(loggerMap)))
+}
+
+// 寫出並關閉所有 Logger 的輸出檔，供應用程式在自身的關閉流程中呼叫
+// 若 ctx 在完成前到期，回傳 ctx.Err()，尚未完成的部分會在背景繼續執行
+func Shutdown(ctx context.Context) error {
+	// 複製 logger 列表後即釋放鎖，逾時後仍在關閉的 logger 不會持續佔用 loggerMu
+	loggerMu.RLock()
+	loggers := make([]*Logger, 0, len(loggerMap))
+
+	for _, logger := range loggerMap {
+		loggers = append(loggers, logger)
+	}
+
+	loggerMu.RUnlock()
+	done := make(chan error, 1)
+
+	go func() {
+		errs := []error{}
+
+		for _, logger := range loggers {
+			if err := logger.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		done <- combineErrors(errs)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "glog.Shutdown 未在期限內完成")
+	}
+}
+
+// 啟用訊號處理: 收到訊號時，寫出並關閉所有 Logger 後，以 exit code 1 結束程式
+// 未給定 signals 時，處理 SIGINT 與 SIGTERM
+// 自行管理關閉流程的應用程式，應改為在流程中呼叫 Shutdown
+func HandleSignals(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	exitChan := make(chan os.Signal, 1)
+	signal.Notify(exitChan, signals...)
+	go exitHandle(exitChan)
 }
 
 // 退出時的處理
-func exitHandle() {
+func exitHandle(exitChan chan os.Signal) {
 	<-exitChan
-	Flush()
+	Shutdown(context.Background())
 	os.Exit(1)
 }
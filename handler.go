@@ -0,0 +1,138 @@
+package glog
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// ====================================================================================================
+// ErrorOp
+// ====================================================================================================
+// 內部錯誤發生的位置
+type ErrorOp byte
+
+const (
+	// 數據寫出
+	OpWrite ErrorOp = iota
+	// 開啟輸出檔
+	OpOpen
+	// 更換輸出檔
+	OpRotate
+	// 寫出緩衝中的數據
+	OpFlush
+	// 執行 Hook
+	OpHook
+)
+
+func (op ErrorOp) String() string {
+	switch op {
+	case OpWrite:
+		return "Write"
+	case OpOpen:
+		return "Open"
+	case OpRotate:
+		return "Rotate"
+	case OpFlush:
+		return "Flush"
+	case OpHook:
+		return "Hook"
+	default:
+		return "Unknown"
+	}
+}
+
+// 發生內部錯誤時呼叫，不應在其中使用同一個 Logger 輸出到檔案，以免遞迴
+type ErrorHandler func(op ErrorOp, err error)
+
+// ====================================================================================================
+// FallbackPolicy
+// ====================================================================================================
+// 寫出檔案失敗時的補救方式
+type FallbackPolicy byte
+
+const (
+	// 不做補救，僅回報錯誤
+	FallbackNone FallbackPolicy = iota
+	// 改為寫出到 stderr
+	FallbackStderr
+	// 關閉輸出檔後重新開啟，再寫出一次
+	FallbackRetry
+	// 改為輸出到備用資料夾，之後的 log 也會輸出到備用資料夾
+	FallbackFolder
+)
+
+func (p FallbackPolicy) String() string {
+	switch p {
+	case FallbackNone:
+		return "FallbackNone"
+	case FallbackStderr:
+		return "FallbackStderr"
+	case FallbackRetry:
+		return "FallbackRetry"
+	case FallbackFolder:
+		return "FallbackFolder"
+	default:
+		return "Unknown"
+	}
+}
+
+// 設置發生內部錯誤時的處理函式
+func (l *Logger) SetErrorHandler(handler ErrorHandler) {
+	l.errorHandler = handler
+}
+
+// 設置寫出檔案失敗時的補救方式，backupFolder 僅在 policy 為 FallbackFolder 時使用
+func (l *Logger) SetFallback(policy FallbackPolicy, backupFolder string) {
+	l.fallback = policy
+	l.backupFolder = backupFolder
+}
+
+// 取得累計發生的內部錯誤次數
+func (l *Logger) ErrorCount() uint64 {
+	return atomic.LoadUint64(&l.errorCount)
+}
+
+// 累計錯誤次數，並呼叫 ErrorHandler
+func (l *Logger) reportError(op ErrorOp, err error) {
+	atomic.AddUint64(&l.errorCount, 1)
+	internalLog("error", NewField("logger", l.loggerName), NewField("op", op), NewField("error", err))
+
+	if l.errorHandler != nil {
+		l.errorHandler(op, err)
+	}
+}
+
+// 回報寫出檔案時發生的錯誤，並依 fallback 嘗試補救，補救成功時回傳 nil
+func (l *Logger) handleFailure(op ErrorOp, err error, output string) error {
+	l.reportError(op, err)
+
+	switch l.fallback {
+	case FallbackStderr:
+		if _, e := os.Stderr.WriteString(output); e != nil {
+			return err
+		}
+		return nil
+
+	case FallbackRetry:
+		// bufio.Writer 發生錯誤後會持續回傳同一個錯誤，因此需關閉後重新建立
+		l.closeOutput()
+
+	case FallbackFolder:
+		if l.backupFolder == "" || l.folder == l.backupFolder {
+			return err
+		}
+
+		l.closeOutput()
+		l.SetFolder(l.backupFolder)
+
+	default:
+		return err
+	}
+
+	if op, err = l.writeFile(output); err != nil {
+		l.reportError(op, err)
+		return err
+	}
+
+	return nil
+}
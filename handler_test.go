@@ -0,0 +1,79 @@
+package glog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 記錄 ErrorHandler 收到的錯誤
+type errorRecorder struct {
+	ops []ErrorOp
+}
+
+func (r *errorRecorder) handle(op ErrorOp, err error) {
+	r.ops = append(r.ops, op)
+}
+
+func (r *errorRecorder) has(op ErrorOp) bool {
+	for _, o := range r.ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func newRotateLogger(t *testing.T) (*Logger, *errorRecorder) {
+	recorder := &errorRecorder{}
+	l := newLogger("rotate", DebugLevel)
+	l.SetOptions(
+		FolderOption(t.TempDir()),
+		BasicOption(InfoLevel, false, true, false),
+		ErrorHandlerOption(recorder.handle, FallbackNone, ""),
+	)
+	// 每筆 log 皆換檔
+	l.SetShiftCondition(ShiftSize, 0, 1)
+	t.Cleanup(func() { l.Close() })
+	return l, recorder
+}
+
+// 換檔時舊輸出檔的寫出與關閉錯誤需回報
+func TestRotateReportsFlushAndCloseErrors(t *testing.T) {
+	l, recorder := newRotateLogger(t)
+	l.Info("first")
+
+	// 模擬磁碟錯誤: 緩衝中仍有數據時，輸出檔已無法寫入
+	l.files[0].Close()
+	l.Info("second")
+
+	if !recorder.has(OpFlush) {
+		t.Errorf("flush error during rotation was not reported, got %v", recorder.ops)
+	}
+
+	if !recorder.has(OpRotate) {
+		t.Errorf("close error during rotation was not reported, got %v", recorder.ops)
+	}
+}
+
+// 兩組輸出檔皆在使用中時，換檔失敗需回報
+func TestRotateReportsBusySlots(t *testing.T) {
+	l, recorder := newRotateLogger(t)
+	l.Info("first")
+
+	busy, err := os.Create(filepath.Join(t.TempDir(), "busy.log"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.files[1] = busy
+
+	if err = l.Logout(InfoLevel, "second"); err == nil {
+		t.Error("rotation with both slots busy returned nil")
+	}
+
+	if !recorder.has(OpRotate) {
+		t.Errorf("rotate error was not reported, got %v", recorder.ops)
+	}
+}
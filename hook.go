@@ -0,0 +1,115 @@
+package glog
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// 呼叫端資訊
+type Caller struct {
+	// 是否成功取得呼叫端
+	Defined bool
+	PC      uintptr
+	File    string
+	Line    int
+	// 完整套件路徑，例如 github.com/j32u4ukh/glog/example/internal
+	PackagePath string
+	// 套件名稱，例如 internal
+	Package string
+	// 方法的接收者型別，一般函式則為空字串
+	Type string
+	// 函式名稱
+	Function string
+}
+
+func newCaller(ok bool, pc uintptr, file string, line int, info *callerInfo) Caller {
+	if !ok {
+		return Caller{}
+	}
+
+	c := Caller{
+		Defined:     true,
+		PC:          pc,
+		File:        file,
+		Line:        line,
+		PackagePath: info.pkgPath,
+		Package:     info.pkg,
+		Type:        info.typ,
+		Function:    info.function,
+	}
+	return c
+}
+
+// 交給 Hook 的一筆 log，Hook 對 Level, Time, Message, Fields 的修改會反映在輸出上
+type Entry struct {
+	LoggerName string
+	Level      LogLevel
+	Time       time.Time
+	Caller     Caller
+	Message    string
+	Fields     []Field
+}
+
+// 每筆 log 輸出前呼叫，可用於增加欄位(hostname, pid 等)或觸發其他動作(統計、告警等)
+// Fire 回傳的錯誤會交給 ErrorHandler，不影響該筆 log 的輸出
+type Hook interface {
+	// 要處理的等級
+	Levels() []LogLevel
+	Fire(entry *Entry) error
+}
+
+// 註冊 Hook，依 Hook.Levels() 套用到對應的等級，同一等級的 Hook 依註冊順序執行
+func (l *Logger) AddHook(hook Hook) {
+	l.hookMu.Lock()
+	defer l.hookMu.Unlock()
+
+	for _, level := range hook.Levels() {
+		l.hooks[level] = append(l.hooks[level], hook)
+	}
+}
+
+func (l *Logger) hasHooks(level LogLevel) bool {
+	l.hookMu.RLock()
+	defer l.hookMu.RUnlock()
+	return len(l.hooks[level]) > 0
+}
+
+func (l *Logger) fireHooks(entry *Entry) {
+	l.hookMu.RLock()
+	hooks := l.hooks[entry.Level]
+	l.hookMu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook.Fire(entry); err != nil {
+			l.reportError(OpHook, errors.Wrapf(err, "執行 Hook 時發生錯誤, logger: %s", l.loggerName))
+		}
+	}
+}
+
+// 以函式實作 Hook
+type hookFunc struct {
+	levels []LogLevel
+	fire   func(entry *Entry) error
+}
+
+// 以函式建立 Hook，未給定 levels 時套用到所有等級
+func NewHook(fire func(entry *Entry) error, levels ...LogLevel) Hook {
+	if len(levels) == 0 {
+		levels = []LogLevel{DebugLevel, InfoLevel, WarnLevel, ErrorLevel}
+	}
+
+	h := &hookFunc{
+		levels: levels,
+		fire:   fire,
+	}
+	return h
+}
+
+func (h *hookFunc) Levels() []LogLevel {
+	return h.levels
+}
+
+func (h *hookFunc) Fire(entry *Entry) error {
+	return h.fire(entry)
+}
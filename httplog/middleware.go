@@ -0,0 +1,173 @@
+// httplog 提供 http.Handler 中介層，將每個請求的資訊經由 *glog.Logger 輸出
+package httplog
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/j32u4ukh/glog"
+	"github.com/pkg/errors"
+)
+
+type requestIDKey struct{}
+
+// 建立中介層，輸出請求的 method, path, status, bytes, latency, remote_ip 與 request_id
+// 5xx 以 Error 等級輸出，4xx 以 Warn 等級輸出，其餘以 Info 等級輸出
+// 請求 ID 會以 glog.WithContext 放入 request 的 context，handler 中可透過 *Ctx 系列函式一併輸出
+func Middleware(logger *glog.Logger, options ...Option) func(http.Handler) http.Handler {
+	c := newConfig()
+
+	for _, option := range options {
+		option.SetOption(c)
+	}
+
+	var healthCount int64
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := r.Header.Get(c.requestIDHeader)
+
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+
+			w.Header().Set(c.requestIDHeader, requestID)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+			ctx = glog.WithContext(ctx, glog.NewField("request_id", requestID))
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+				size:           0,
+			}
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			// 健康檢查的請求，成功時只抽樣輸出
+			if _, ok := c.healthPaths[r.URL.Path]; ok && rw.status < http.StatusBadRequest {
+				if c.healthEvery <= 0 {
+					return
+				}
+
+				// 第 1 次輸出，之後每 healthEvery 次輸出一次
+				if (atomic.AddInt64(&healthCount, 1)-1)%c.healthEvery != 0 {
+					return
+				}
+			}
+
+			ctx = glog.WithContext(ctx,
+				glog.NewField("method", r.Method),
+				glog.NewField("path", r.URL.Path),
+				glog.NewField("status", rw.status),
+				glog.NewField("bytes", rw.size),
+				glog.NewField("latency", time.Since(start)),
+				glog.NewField("remote_ip", c.remoteIP(r)),
+			)
+
+			switch {
+			case rw.status >= http.StatusInternalServerError:
+				logger.ErrorCtx(ctx, "%s %s", r.Method, r.URL.Path)
+			case rw.status >= http.StatusBadRequest:
+				logger.WarnCtx(ctx, "%s %s", r.Method, r.URL.Path)
+			default:
+				logger.InfoCtx(ctx, "%s %s", r.Method, r.URL.Path)
+			}
+		})
+	}
+}
+
+// 取得中介層產生或沿用的請求 ID
+func GetRequestID(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+func (c *config) remoteIP(r *http.Request) string {
+	if c.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(ip)
+		}
+
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func newRequestID() string {
+	buffer := make([]byte, 16)
+
+	if _, err := rand.Read(buffer); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(buffer)
+}
+
+// 記錄回應的狀態碼與大小
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// 供 WebSocket 等需要接管連線的 handler 使用
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("原本的 ResponseWriter 不支援 http.Hijacker")
+}
+
+// HTTP/2 的 server push
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// 供 http.ResponseController 取得原本的 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,261 @@
+package httplog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/j32u4ukh/glog"
+)
+
+// 記錄每筆 log 的 Hook
+type recordHook struct {
+	mu      sync.Mutex
+	entries []glog.Entry
+}
+
+func (h *recordHook) Levels() []glog.LogLevel {
+	return []glog.LogLevel{glog.DebugLevel, glog.InfoLevel, glog.WarnLevel, glog.ErrorLevel}
+}
+
+func (h *recordHook) Fire(entry *glog.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, *entry)
+	return nil
+}
+
+func (h *recordHook) take() []glog.Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := h.entries
+	h.entries = nil
+	return entries
+}
+
+func fieldValue(entry glog.Entry, key string) any {
+	for _, field := range entry.Fields {
+		if field.Key == key {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+func newTestLogger(t *testing.T) (*glog.Logger, *recordHook) {
+	hook := &recordHook{}
+	logger := glog.SetLogger(0, "httplog", glog.DebugLevel)
+	logger.SetConsoleWriter(io.Discard)
+	logger.AddHook(hook)
+	t.Cleanup(func() { glog.RemoveLogger(0) })
+	return logger, hook
+}
+
+// 回傳指定狀態碼的 handler
+func statusHandler(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("body"))
+	})
+}
+
+func TestMiddlewareLevels(t *testing.T) {
+	tests := []struct {
+		status int
+		level  glog.LogLevel
+	}{
+		{http.StatusOK, glog.InfoLevel},
+		{http.StatusFound, glog.InfoLevel},
+		{http.StatusBadRequest, glog.WarnLevel},
+		{http.StatusNotFound, glog.WarnLevel},
+		{http.StatusInternalServerError, glog.ErrorLevel},
+		{http.StatusServiceUnavailable, glog.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			logger, hook := newTestLogger(t)
+			handler := Middleware(logger)(statusHandler(tt.status))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+			entries := hook.take()
+
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+
+			entry := entries[0]
+
+			if entry.Level != tt.level {
+				t.Errorf("level = %v, want %v", entry.Level, tt.level)
+			}
+
+			checks := []struct {
+				key  string
+				want any
+			}{
+				{"method", http.MethodPost},
+				{"path", "/orders"},
+				{"status", tt.status},
+				{"bytes", int64(4)},
+			}
+
+			for _, check := range checks {
+				if value := fieldValue(entry, check.key); value != check.want {
+					t.Errorf("%s = %v, want %v", check.key, value, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		options  []Option
+		incoming string
+	}{
+		{"propagated", "X-Request-ID", nil, "abc-123"},
+		{"generated", "X-Request-ID", nil, ""},
+		{"custom header", "X-Trace", []Option{RequestIDOption("X-Trace")}, "trace-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := newTestLogger(t)
+			var handlerID string
+			handler := Middleware(logger, tt.options...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerID = GetRequestID(r.Context())
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if tt.incoming != "" {
+				request.Header.Set(tt.header, tt.incoming)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			responseID := recorder.Header().Get(tt.header)
+
+			if tt.incoming != "" && responseID != tt.incoming {
+				t.Errorf("response id = %q, want %q", responseID, tt.incoming)
+			}
+
+			// 產生的請求 ID 為 16 bytes 的 hex 字串
+			if tt.incoming == "" && len(responseID) != 32 {
+				t.Errorf("generated id = %q, want 32 hex characters", responseID)
+			}
+
+			if handlerID != responseID {
+				t.Errorf("GetRequestID = %q, want %q", handlerID, responseID)
+			}
+
+			entries := hook.take()
+
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+
+			if id := fieldValue(entries[0], "request_id"); id != responseID {
+				t.Errorf("request_id field = %v, want %q", id, responseID)
+			}
+		})
+	}
+}
+
+func TestMiddlewareHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		every   int64
+		status  int
+		request int
+		want    int
+	}{
+		// 第 1 次輸出，之後每 3 次輸出一次: 第 1, 4, 7 次
+		{"sampled", 3, http.StatusOK, 7, 3},
+		{"disabled", 0, http.StatusOK, 5, 0},
+		// 失敗的健康檢查一律輸出
+		{"failure", 3, http.StatusServiceUnavailable, 4, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := newTestLogger(t)
+			handler := Middleware(logger, HealthCheckOption(tt.every, "/healthz"))(statusHandler(tt.status))
+
+			for i := 0; i < tt.request; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			}
+
+			if entries := hook.take(); len(entries) != tt.want {
+				t.Errorf("logged %d entries, want %d", len(entries), tt.want)
+			}
+
+			// 非健康檢查路徑不受影響
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+			if entries := hook.take(); len(entries) != 1 {
+				t.Errorf("logged %d entries for a normal path, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestResponseWriterFlush(t *testing.T) {
+	logger, _ := newTestLogger(t)
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !recorder.Flushed {
+		t.Error("Flush was not delegated to the underlying ResponseWriter")
+	}
+}
+
+func TestResponseWriterHijack(t *testing.T) {
+	logger, _ := newTestLogger(t)
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		defer conn.Close()
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked"))
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+
+	if string(body) != "hijacked" {
+		t.Errorf("body = %q, want %q", body, "hijacked")
+	}
+}
+
+// 原本的 ResponseWriter 不支援時，需回傳錯誤而非 panic
+func TestResponseWriterUnsupported(t *testing.T) {
+	w := &responseWriter{ResponseWriter: httptest.NewRecorder(), status: http.StatusOK}
+
+	if _, _, err := w.Hijack(); err == nil {
+		t.Error("Hijack on a ResponseWriter without http.Hijacker returned nil error")
+	}
+
+	if err := w.Push("/style.css", nil); err != http.ErrNotSupported {
+		t.Errorf("Push returned %v, want %v", err, http.ErrNotSupported)
+	}
+}
@@ -0,0 +1,89 @@
+package httplog
+
+type Option interface {
+	SetOption(*config)
+}
+
+type config struct {
+	// 讀取與回傳請求 ID 所用的 Header
+	requestIDHeader string
+	// 是否信任 X-Forwarded-For 與 X-Real-IP 來判斷來源 IP
+	trustProxy bool
+	// 健康檢查路徑
+	healthPaths map[string]struct{}
+	// 健康檢查每 healthEvery 次才輸出一次，小於等於 0 表示完全不輸出
+	healthEvery int64
+}
+
+func newConfig() *config {
+	c := &config{
+		requestIDHeader: "X-Request-ID",
+		trustProxy:      false,
+		healthPaths: map[string]struct{}{
+			"/health":  {},
+			"/healthz": {},
+			"/livez":   {},
+			"/readyz":  {},
+		},
+		healthEvery: 100,
+	}
+	return c
+}
+
+type requestIDOption struct {
+	header string
+}
+
+// 設置讀取與回傳請求 ID 所用的 Header，預設為 X-Request-ID
+func RequestIDOption(header string) *requestIDOption {
+	o := &requestIDOption{
+		header: header,
+	}
+	return o
+}
+
+func (o *requestIDOption) SetOption(c *config) {
+	c.requestIDHeader = o.header
+}
+
+type trustProxyOption struct {
+	trust bool
+}
+
+// 位於反向代理之後時，可信任 X-Forwarded-For 與 X-Real-IP 來判斷來源 IP
+func TrustProxyOption(trust bool) *trustProxyOption {
+	o := &trustProxyOption{
+		trust: trust,
+	}
+	return o
+}
+
+func (o *trustProxyOption) SetOption(c *config) {
+	c.trustProxy = o.trust
+}
+
+type healthCheckOption struct {
+	every int64
+	paths []string
+}
+
+// 設置健康檢查路徑，每 every 次才輸出一次，every 小於等於 0 表示完全不輸出
+// 未給定 paths 時，沿用預設的 /health, /healthz, /livez, /readyz
+func HealthCheckOption(every int64, paths ...string) *healthCheckOption {
+	o := &healthCheckOption{
+		every: every,
+		paths: paths,
+	}
+	return o
+}
+
+func (o *healthCheckOption) SetOption(c *config) {
+	c.healthEvery = o.every
+
+	if len(o.paths) > 0 {
+		c.healthPaths = make(map[string]struct{}, len(o.paths))
+		for _, p := range o.paths {
+			c.healthPaths[p] = struct{}{}
+		}
+	}
+}
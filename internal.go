@@ -0,0 +1,31 @@
+package glog
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// 內部診斷訊息的輸出位置，預設為 nil，即不輸出
+var internalWriter io.Writer
+var internalMu sync.Mutex
+
+// 設置 glog 內部診斷訊息(開檔、換檔、內部錯誤等)的輸出位置，設為 nil 則停止輸出
+// 每個事件輸出為一行: 時間 glog | 事件名稱 | key=value ...
+func SetInternalLogger(w io.Writer) {
+	internalMu.Lock()
+	defer internalMu.Unlock()
+	internalWriter = w
+}
+
+func internalLog(event string, fields ...Field) {
+	internalMu.Lock()
+	defer internalMu.Unlock()
+
+	if internalWriter == nil {
+		return
+	}
+
+	fmt.Fprintf(internalWriter, "%s glog | %s | %s\n", time.Now().Format(time.RFC3339Nano), event, formatFields(fields))
+}
@@ -3,6 +3,7 @@ package glog
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"os"
@@ -19,7 +20,7 @@ type void struct{}
 
 var null void
 
-// 時間輸出格式
+// 預設的時間輸出格式
 const DISPLAYTIME string = "2006/01/02 15:04:05"
 
 // 檔名時間格式，間隔時間類型為 Second 的設置只在開發期間使用，因此檔名時間格式精細度到分鐘即可
@@ -30,10 +31,15 @@ const FILENAMETIME string = "2006-01-02-15-04"
 // 2. 是否輸出成檔案
 // 3. 是否輸出檔案資訊
 // 3. 是否輸出行數資訊
+// 5. 是否輸出堆疊
 const TOCONSOLE int = 0b0001
 const TOFILE int = 0b0010
 const FILEINFO int = 0b0100
 const LINEINFO int = 0b1000
+const STACKTRACE int = 0b10000
+
+// glog 本身的套件路徑，輸出堆疊時會略過此套件中的函式
+const glogPackage string = "github.com/j32u4ukh/glog"
 
 // ====================================================================================================
 // 時間轉換
@@ -127,6 +133,14 @@ func (st ShiftType) String() string {
 // ====================================================================================================
 
 type Logger struct {
+	// 設定與輸出狀態，由 WithCallerSkip 產生的 Logger 與原 Logger 共用
+	*loggerCore
+	// 額外略過的呼叫層數，用於包裝 Logger 的型別，只影響此 Logger
+	callerSkip int
+}
+
+// Logger 的設定與輸出狀態
+type loggerCore struct {
 	// 輸出資料夾
 	folder string
 	// logger 名稱
@@ -136,6 +150,68 @@ type Logger struct {
 	// UTC 時區
 	loc *time.Location
 	utc float32
+	// 時間來源，可替換以便測試
+	clock Clock
+	// 時間輸出格式，可為 time 的 layout 或 TimeUnix 等
+	timeFormat string
+	// 輸出堆疊時的最大深度
+	stackDepth int
+
+	// ==================================================
+	// 呼叫端定位
+	// ==================================================
+	// 透過 Helper 標記的函式，定位呼叫端時會被略過
+	helpers  map[string]void
+	helperMu sync.RWMutex
+	// 輸出檔案資訊時，檔案路徑的呈現方式
+	pathMode CallerPathMode
+
+	// ==================================================
+	// 內部錯誤處理
+	// ==================================================
+	// 寫出、開檔、換檔、寫出緩衝失敗時呼叫
+	errorHandler ErrorHandler
+	// 累計發生的內部錯誤次數
+	errorCount uint64
+	// 寫出檔案失敗時的補救方式
+	fallback FallbackPolicy
+	// fallback 為 FallbackFolder 時，改為輸出到此資料夾
+	backupFolder string
+
+	// ==================================================
+	// 輸出量控制
+	// ==================================================
+	// 抽樣，為 nil 時不抽樣
+	sampler *sampler
+	// 依等級限制輸出速率，為 nil 時不限制
+	limiter *rateLimiter
+	// 合併連續重複的訊息，為 nil 時不合併
+	deduper *deduper
+	// 依呼叫端套件、函式、訊息或欄位過濾，為 nil 時不過濾
+	filters *filterSet
+
+	// 敏感資料遮蔽，為 nil 時不遮蔽
+	redactor *Redactor
+
+	// ==================================================
+	// Console 輸出
+	// ==================================================
+	// 各個 Level 輸出到 Console 的位置
+	consoles  map[LogLevel]io.Writer
+	consoleMu sync.Mutex
+	colorMode ColorMode
+	// 各個 Level 依 colorMode 與輸出位置是否為終端機，決定是否上色
+	colors  map[LogLevel]bool
+	palette *Palette
+	// 開發用的 Console 格式，為 nil 時與檔案格式相同
+	pretty *prettyEncoder
+
+	// ==================================================
+	// Hook
+	// ==================================================
+	// 各個 Level 註冊的 Hook
+	hooks  map[LogLevel][]Hook
+	hookMu sync.RWMutex
 
 	// ==================================================
 	// 各個 Level 的設定
@@ -181,12 +257,22 @@ type Logger struct {
 }
 
 func newLogger(loggerName string, level LogLevel, options ...Option) *Logger {
-	l := &Logger{
+	core := &loggerCore{
 		folder:     "",
 		loggerName: loggerName,
 		level:      level,
 		loc:        time.UTC,
 		utc:        0,
+		clock:      systemClock{},
+		timeFormat: DISPLAYTIME,
+		stackDepth: 32,
+		helpers:    map[string]void{},
+		hooks:      map[LogLevel][]Hook{},
+		pathMode:   PathFull,
+		fallback:   FallbackNone,
+		consoles:   map[LogLevel]io.Writer{},
+		colors:     map[LogLevel]bool{},
+		palette:    DefaultPalette(),
 		outputs: map[LogLevel]int{
 			DebugLevel: TOCONSOLE | LINEINFO,
 			InfoLevel:  TOCONSOLE | LINEINFO,
@@ -203,6 +289,12 @@ func newLogger(loggerName string, level LogLevel, options ...Option) *Logger {
 		sizeLimit:    0,
 		cumSize:      0,
 	}
+	l := &Logger{
+		loggerCore: core,
+		callerSkip: 0,
+	}
+	l.SetConsole(ConsoleStdout)
+	l.SetColorMode(ColorAuto)
 	return l
 }
 
@@ -223,6 +315,54 @@ func (l *Logger) SetFolder(folder string) {
 	l.folder = folder
 }
 
+// 設置定位呼叫端時，額外略過的呼叫層數
+// 例如包裝 Logger 的型別只有一層時，設為 1 即可輸出包裝函式的呼叫端
+// 會影響所有使用此 Logger 的呼叫端，包裝共用的 Logger 時應使用 WithCallerSkip
+func (l *Logger) SetCallerSkip(skip int) {
+	l.callerSkip = skip
+}
+
+// 回傳額外再略過 skip 層呼叫端的 Logger，與原 Logger 共用設定與輸出狀態
+// 原 Logger 與其他呼叫端的呼叫端資訊不受影響
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	derived := &Logger{
+		loggerCore: l.loggerCore,
+		callerSkip: l.callerSkip + skip,
+	}
+	return derived
+}
+
+// 設置輸出檔案資訊時，檔案路徑的呈現方式
+func (l *Logger) SetCallerPathMode(mode CallerPathMode) {
+	l.pathMode = mode
+}
+
+// 將呼叫此函式的函式標記為輔助函式(類似 testing.T.Helper)，定位呼叫端時會略過該函式
+func (l *Logger) Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+
+	if !ok {
+		return
+	}
+
+	name := runtime.FuncForPC(pc).Name()
+
+	l.helperMu.RLock()
+	_, ok = l.helpers[name]
+	l.helperMu.RUnlock()
+
+	if !ok {
+		l.helperMu.Lock()
+		l.helpers[name] = null
+		l.helperMu.Unlock()
+	}
+}
+
+// 設置輸出堆疊時的最大深度
+func (l *Logger) SetStackDepth(depth int) {
+	l.stackDepth = depth
+}
+
 func (l *Logger) SetBufferSize(size uint16) {
 	l.bufferSize = size
 }
@@ -301,97 +441,256 @@ func (l *Logger) setSencodInterval(second int64) {
 }
 
 func (l *Logger) Debug(message string, a ...any) {
-	l.Logout(DebugLevel, fmt.Sprintf(message, a...))
+	l.logout(2, &entry{level: DebugLevel, message: message, args: a})
 }
 
 func (l *Logger) Info(message string, a ...any) {
-	l.Logout(InfoLevel, fmt.Sprintf(message, a...))
+	l.logout(2, &entry{level: InfoLevel, message: message, args: a})
 }
 
 func (l *Logger) Warn(message string, a ...any) {
-	l.Logout(WarnLevel, fmt.Sprintf(message, a...))
+	l.logout(2, &entry{level: WarnLevel, message: message, args: a})
 }
 
 func (l *Logger) Error(message string, a ...any) {
-	l.Logout(ErrorLevel, fmt.Sprintf(message, a...))
+	l.logout(2, &entry{level: ErrorLevel, message: message, args: a})
 }
 
 func (l *Logger) Logout(level LogLevel, message string) error {
-	if l.level > level {
+	return l.logout(2, &entry{level: level, message: message})
+}
+
+// 一筆待輸出的 log
+type entry struct {
+	level LogLevel
+	// 有 args 時為格式字串，在通過等級與抽樣的檢查後才進行格式化
+	message string
+	args    []any
+	// 附加在訊息之後的欄位
+	fields []Field
+	// 接在該行之後輸出的多行內容，例如錯誤的堆疊
+	stack []string
+	// 由 glog 產生的統計訊息(例如抽樣略過的數量)，不經過抽樣等過濾，也不輸出呼叫端
+	summary bool
+	// 不輸出呼叫端與堆疊，例如經由 Writer 寫入的內容，呼叫 Write 的函式並非訊息的來源
+	noCaller bool
+	// 輸出時間，為零值時使用當下時間
+	time time.Time
+}
+
+// skip 為 runtime.Caller 的參數，用於定位呼叫端(0: logout 本身)
+func (l *Logger) logout(skip int, e *entry) error {
+	level, message := e.level, e.message
+
+	if l.minLevel() > level {
 		return nil
 	}
 
-	pc, file, line, ok := runtime.Caller(2)
-	timeStamp := l.getTime().Format(DISPLAYTIME)
-	var output string
+	var pc uintptr
+	var file string
+	var line int
+	var info *callerInfo
+	ok := false
+
+	if !e.summary && !e.noCaller {
+		skip = l.getCallerSkip(skip)
+		pc, file, line, ok = runtime.Caller(skip)
+	}
 
 	if ok {
-		funcName := runtime.FuncForPC(pc).Name()
-		names := strings.Split(funcName, ".")
-		var label, pkg string
-		var temp []string
-
-		if len(names) == 2 {
-			temp = strings.Split(names[0], "/")
-			pkg = temp[len(temp)-1]
-			label = fmt.Sprintf("[%s] %s", pkg, names[1])
-		} else {
-			temp = strings.Split(names[1], "/")
-			pkg = temp[len(temp)-1]
-			label = fmt.Sprintf("[%s] %s", pkg, names[2])
+		info = getCallerInfo(pc)
+	} else {
+		info = &callerInfo{}
+	}
+
+	// 依呼叫端套件決定輸出等級
+	if !e.summary && l.filters != nil && l.filters.packageLevel(info, l.level) > level {
+		return nil
+	}
+
+	// 依呼叫端、訊息或欄位過濾，需在抽樣與速率限制之前，被過濾的 log 才不會佔用抽樣次數與速率限制的額度
+	// 訊息過濾需使用格式化後的訊息，因此有過濾規則時提前格式化
+	formatted := false
+
+	if !e.summary && l.filters != nil {
+		if e.args != nil {
+			message = fmt.Sprintf(message, e.args...)
+			formatted = true
 		}
 
-		if l.outputs[level]&FILEINFO == FILEINFO {
-			message = fmt.Sprintf("%s | %s", message, file)
+		if !l.filters.allow(info, message, e.fields) {
+			return nil
 		}
+	}
+
+	if !e.summary && l.sampler != nil {
+		allowed, reports := l.sampler.check(level, e.message, l.getTime())
 
-		if l.outputs[level]&LINEINFO == LINEINFO {
-			message = fmt.Sprintf("%s | (%d)", message, line)
+		for _, report := range reports {
+			l.logout(0, report)
 		}
 
-		output = fmt.Sprintf("%s %s | %s | %s\n", timeStamp, level, label, message)
-	} else {
-		output = fmt.Sprintf("%s %s | %s\n", timeStamp, level, message)
+		if !allowed {
+			return nil
+		}
+	}
+
+	// 被抽樣略過的 log 不佔用速率限制的額度，因此在抽樣之後檢查
+	if !e.summary && l.limiter != nil {
+		allowed, report := l.limiter.check(level, l.getTime())
+
+		if !allowed {
+			return nil
+		}
+
+		if report != nil {
+			l.logout(0, report)
+		}
+	}
+
+	if e.args != nil && !formatted {
+		message = fmt.Sprintf(message, e.args...)
+	}
+
+	now := e.time
+
+	if now.IsZero() {
+		now = l.getTime()
+	}
+
+	fields := e.fields
+
+	// 遮蔽敏感資料，Hook 與各輸出位置皆只會看到遮蔽後的內容
+	// 統計訊息中可能含有原始訊息(例如抽樣的格式字串)，同樣需要遮蔽
+	if l.redactor != nil {
+		message, fields = l.redactor.redact(message, fields)
+	}
+
+	// Hook 可修改等級、時間、訊息與欄位
+	if !e.summary && l.hasHooks(level) {
+		hookEntry := &Entry{
+			LoggerName: l.loggerName,
+			Level:      level,
+			Time:       now,
+			Caller:     newCaller(ok, pc, file, line, info),
+			Message:    message,
+			Fields:     append([]Field{}, fields...),
+		}
+		l.fireHooks(hookEntry)
+
+		// Hook 新增或改寫的訊息與欄位，同樣需要遮蔽
+		if l.redactor != nil {
+			hookEntry.Message, hookEntry.Fields = l.redactor.redactChanged(message, fields, hookEntry.Message, hookEntry.Fields)
+		}
+
+		level, now, message, fields = hookEntry.Level, hookEntry.Time, hookEntry.Message, hookEntry.Fields
+	}
+
+	// 同一呼叫端連續輸出相同訊息時，只輸出第一筆，之後以重複次數取代
+	if !e.summary && l.deduper != nil {
+		allowed, report := l.deduper.check(dedupKey{level: level, pc: pc, message: message, fields: formatFields(fields)}, now)
+
+		// 統計訊息輸出在該筆 log 之前，時間不可晚於該筆 log
+		if report != nil {
+			report.time = now
+			l.logout(0, report)
+		}
+
+		if !allowed {
+			return nil
+		}
+	}
+
+	r := &record{
+		now:     now,
+		time:    l.formatTime(now),
+		level:   level,
+		message: message,
+		fields:  fields,
+		stack:   e.stack,
 	}
 
+	if ok {
+		r.label = info.label()
+		r.shortLocation = fmt.Sprintf("%s:%d", path.Base(file), line)
+
+		// 同時輸出檔案與行數時，合併為 file.go:42 的形式
+		switch l.outputs[level] & (FILEINFO | LINEINFO) {
+		case FILEINFO | LINEINFO:
+			r.location = fmt.Sprintf("%s:%d", info.filePath(file, l.pathMode), line)
+		case FILEINFO:
+			r.location = info.filePath(file, l.pathMode)
+		case LINEINFO:
+			r.location = fmt.Sprintf("(%d)", line)
+		}
+	}
+
+	// 錯誤本身已帶有堆疊時，不再重複輸出
+	if l.outputs[level]&STACKTRACE == STACKTRACE && len(r.stack) == 0 && !e.summary && !e.noCaller {
+		r.stack = l.getStack(skip)
+	}
+
+	output := r.format(nil)
+
 	// 是否輸出到 Console
 	if l.outputs[level]&TOCONSOLE == TOCONSOLE {
-		fmt.Print(output)
+		var palette *Palette
+
+		if l.colors[level] {
+			palette = l.palette
+		}
+
+		if l.pretty != nil {
+			l.writeConsole(level, l.pretty.encode(r, palette))
+		} else if palette != nil {
+			l.writeConsole(level, r.format(palette))
+		} else {
+			l.writeConsole(level, output)
+		}
 	}
 
 	// 是否輸出到檔案
 	if l.outputs[level]&TOFILE == TOFILE {
-		if l.outputInited {
-			status := l.whetherNeedUpdateOutputs()
+		if op, err := l.writeFile(output); err != nil {
+			return l.handleFailure(op, err, output)
+		}
+	}
+	return nil
+}
 
-			// 檢查是否需要更新輸出位置
-			if status != 0 {
-				// 更新輸出位置
-				err := l.updateOutput(status)
+// 將數據寫出到檔案，必要時建立或更換輸出檔，發生錯誤時一併回傳錯誤發生的位置
+func (l *Logger) writeFile(output string) (ErrorOp, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-				if err != nil {
-					return errors.Wrap(err, "更新輸出位置時發生錯誤")
-				}
-			}
-		} else {
-			err := l.initOutput()
+	if l.outputInited {
+		status := l.whetherNeedUpdateOutputs()
+
+		// 檢查是否需要更新輸出位置
+		if status != 0 {
+			// 更新輸出位置
+			err := l.updateOutput(status)
 
 			if err != nil {
-				return errors.Wrap(err, "Failed to initialize output.")
+				return OpRotate, errors.Wrap(err, "更新輸出位置時發生錯誤")
 			}
 		}
-
-		l.mu.Lock()
-		defer l.mu.Unlock()
-		size, err := l.writer.WriteString(output)
+	} else {
+		err := l.initOutput()
 
 		if err != nil {
-			return errors.Wrapf(err, "數據寫出時發生錯誤")
+			return OpOpen, errors.Wrap(err, "Failed to initialize output.")
 		}
+	}
+
+	size, err := l.writer.WriteString(output)
 
-		l.cumSize += int64(size)
+	if err != nil {
+		return OpWrite, errors.Wrapf(err, "數據寫出時發生錯誤")
 	}
-	return nil
+
+	l.cumSize += int64(size)
+	return OpWrite, nil
 }
 
 // 可使用 runtime.FuncForPC(ptr) 獲得進一步的資訊
@@ -422,6 +721,76 @@ func (l *Logger) CheckCallers() {
 	}
 }
 
+// 加上額外略過的層數，並略過以 Helper 標記的函式
+// skip 的意義與 runtime.Caller 相同(0: getCallerSkip 的呼叫端)，回傳值亦同
+func (l *Logger) getCallerSkip(skip int) int {
+	skip += l.callerSkip
+
+	l.helperMu.RLock()
+	defer l.helperMu.RUnlock()
+
+	if len(l.helpers) == 0 {
+		return skip
+	}
+
+	for {
+		pc, _, _, ok := runtime.Caller(skip + 1)
+
+		if !ok {
+			return skip
+		}
+
+		if _, ok = l.helpers[runtime.FuncForPC(pc).Name()]; !ok {
+			return skip
+		}
+
+		skip++
+	}
+}
+
+// 取得呼叫端的堆疊，略過 glog 本身的函式，最多 stackDepth 層
+// skip 的意義與 runtime.Caller 相同(0: getStack 的呼叫端)
+func (l *Logger) getStack(skip int) []string {
+	if l.stackDepth <= 0 {
+		return nil
+	}
+
+	// 多取一些，以扣除被略過的 glog 函式
+	pcs := make([]uintptr, l.stackDepth+8)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]string, 0, 2*l.stackDepth)
+	depth := 0
+
+	for depth < l.stackDepth {
+		frame, more := frames.Next()
+
+		if isGlogFunction(frame.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+
+		stack = append(stack, frame.Function, fmt.Sprintf("\t%s:%d", frame.File, frame.Line))
+		depth++
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// 是否為 glog 本身(不含子套件)的函式
+func isGlogFunction(funcName string) bool {
+	if !strings.HasPrefix(funcName, glogPackage+".") {
+		return false
+	}
+	return !strings.Contains(funcName[len(glogPackage):], "/")
+}
+
 // 初始化輸出結構
 func (l *Logger) initOutput() error {
 	if l.folder == "" {
@@ -437,7 +806,7 @@ func (l *Logger) initOutput() error {
 	}
 
 	filePath := l.getInitPath()
-	fmt.Printf("(l *Logger) initOutput | cumSize: %d, filePath: %s\n", l.cumSize, filePath)
+	internalLog("init_output", NewField("logger", l.loggerName), NewField("cum_size", l.cumSize), NewField("path", filePath))
 
 	l.files[0], err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
 
@@ -466,14 +835,14 @@ func (l *Logger) getFilePath() string {
 		var fileName string
 
 		if l.nShift == 0 {
-			fmt.Printf("當前時間區段內首次取得路徑\n")
 			fileName = fmt.Sprintf("%s-%s.log", l.loggerName, timeStamp)
 		} else {
 			fileName = fmt.Sprintf("%s-%s-%d.log", l.loggerName, timeStamp, l.nShift)
-			fmt.Printf("第 %d 次取得路徑, fileName: %s\n", l.nShift, fileName)
 			l.nShift++
 		}
 
+		internalLog("shift_path", NewField("logger", l.loggerName), NewField("n_shift", l.nShift), NewField("file", fileName))
+
 		filePath = path.Join(l.folder, fileName)
 
 	default:
@@ -491,7 +860,7 @@ func (l *Logger) getInitPath() string {
 			continue
 		}
 		names[file.Name()] = null
-		fmt.Printf("(l *Logger) getInitPath | Existed file: %s\n", file.Name())
+		internalLog("existed_file", NewField("logger", l.loggerName), NewField("file", file.Name()))
 	}
 
 	timeStamp := l.getFileTime()
@@ -524,7 +893,7 @@ func (l *Logger) getInitPath() string {
 	}
 
 	filePath = path.Join(l.folder, fileName)
-	fmt.Printf("(l *Logger) getInitPath | filePath1: %s\n", filePath)
+	internalLog("init_path_candidate", NewField("logger", l.loggerName), NewField("path", filePath))
 	stat, err = os.Stat(filePath)
 
 	// 若該檔名已存在
@@ -532,7 +901,7 @@ func (l *Logger) getInitPath() string {
 		// 更新累積檔案大小
 		l.cumSize = stat.Size()
 		status := l.whetherNeedUpdateOutputs()
-		fmt.Printf("(l *Logger) getInitPath | status: %d, cumSize: %d, filePath: %s\n", status, l.cumSize, filePath)
+		internalLog("init_path_existed", NewField("logger", l.loggerName), NewField("status", status), NewField("cum_size", l.cumSize), NewField("path", filePath))
 
 		// 若已達換檔達條件
 		if status != 0 {
@@ -544,7 +913,7 @@ func (l *Logger) getInitPath() string {
 	}
 
 	l.nShift++
-	fmt.Printf("(l *Logger) getInitPath | nShift: %d, cumSize: %d, filePath2: %s\n", l.nShift, l.cumSize, filePath)
+	internalLog("init_path", NewField("logger", l.loggerName), NewField("n_shift", l.nShift), NewField("cum_size", l.cumSize), NewField("path", filePath))
 	return filePath
 }
 
@@ -615,10 +984,11 @@ func (l *Logger) updateOutput(status byte) error {
 	} else if l.files[1] == nil {
 		idx = 1
 	} else {
-		return errors.Wrap(err, "切換輸出檔時發生錯誤")
+		return errors.New("切換輸出檔時發生錯誤, 兩組輸出檔皆在使用中")
 	}
 
 	newPath := l.getFilePath()
+	internalLog("rotate", NewField("logger", l.loggerName), NewField("status", status), NewField("path", newPath))
 	l.files[idx], err = os.OpenFile(newPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
 
 	if err != nil {
@@ -628,27 +998,91 @@ func (l *Logger) updateOutput(status byte) error {
 	l.writers[idx] = bufio.NewWriterSize(l.files[idx], int(l.bufferSize))
 	l.writer = l.writers[idx]
 
-	// 清空並關閉另一組 logger
+	// 清空並關閉另一組 logger，新的輸出檔已可使用，因此只回報錯誤，不中斷該筆輸出
 	idx = 1 - idx
-	if l.writers[idx].Buffered() > 0 {
-		l.writers[idx].Flush()
+
+	if err = l.writers[idx].Flush(); err != nil {
+		l.reportError(OpFlush, errors.Wrapf(err, "換檔時寫出緩衝數據發生錯誤, logger: %s", l.loggerName))
 	}
+
 	l.writers[idx] = nil
-	l.files[idx].Close()
+
+	if err = l.files[idx].Close(); err != nil {
+		l.reportError(OpRotate, errors.Wrapf(err, "換檔時關閉輸出檔發生錯誤, logger: %s", l.loggerName))
+	}
+
 	l.files[idx] = nil
 	return nil
 }
 
+// 寫出緩衝中的數據，輸出檔維持開啟，可繼續輸出
 func (l *Logger) Flush() {
-	var idx int
-	for idx, l.writer = range l.writers {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, writer := range l.writers {
+		if (writer != nil) && (writer.Buffered() > 0) {
+			if err := writer.Flush(); err != nil {
+				l.reportError(OpFlush, errors.Wrapf(err, "寫出緩衝數據時發生錯誤, logger: %s", l.loggerName))
+			}
+		}
+	}
+}
+
+// 寫出緩衝中的數據並關閉輸出檔
+// 關閉後仍可繼續使用，下一筆輸出到檔案的 log 會重新建立輸出檔
+func (l *Logger) Close() error {
+	// 先輸出尚未輸出的抽樣統計
+	if l.sampler != nil {
+		for _, report := range l.sampler.flush(l.getTime()) {
+			l.logout(0, report)
+		}
+	}
+
+	// 先輸出尚未輸出的速率限制統計
+	if l.limiter != nil {
+		for _, report := range l.limiter.flush(l.getTime()) {
+			l.logout(0, report)
+		}
+	}
+
+	// 先輸出尚未輸出的重複次數
+	if l.deduper != nil {
+		if report := l.deduper.flush(); report != nil {
+			l.logout(0, report)
+		}
+	}
+
+	return l.closeOutput()
+}
+
+// 寫出緩衝中的數據並關閉輸出檔，重置輸出狀態，下一筆輸出時會重新建立輸出檔
+func (l *Logger) closeOutput() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	errs := []error{}
+
+	for idx, writer := range l.writers {
+		if writer != nil {
+			if err := writer.Flush(); err != nil {
+				errs = append(errs, errors.Wrapf(err, "寫出緩衝數據時發生錯誤, logger: %s", l.loggerName))
+			}
+			l.writers[idx] = nil
+		}
+
 		if l.files[idx] != nil {
-			if (l.writer != nil) && (l.writer.Buffered() > 0) {
-				l.writer.Flush()
+			if err := l.files[idx].Close(); err != nil {
+				errs = append(errs, errors.Wrapf(err, "關閉輸出檔時發生錯誤, logger: %s", l.loggerName))
 			}
-			l.files[idx].Close()
+			l.files[idx] = nil
 		}
 	}
+
+	l.writer = nil
+	l.outputInited = false
+	l.nShift = 0
+	l.cumSize = 0
+	return combineErrors(errs)
 }
 
 func (l *Logger) setUtc(utc float32) {
@@ -662,5 +1096,5 @@ func (l *Logger) setUtc(utc float32) {
 }
 
 func (l *Logger) getTime() time.Time {
-	return time.Now().In(l.loc)
+	return l.clock.Now().In(l.loc)
 }
@@ -1,6 +1,9 @@
 package glog
 
-import "fmt"
+import (
+	"io"
+	"time"
+)
 
 type Option interface {
 	SetOption(*Logger)
@@ -43,7 +46,6 @@ func (o *basicOption) SetOption(logger *Logger) {
 	if o.ToFile {
 		state |= TOFILE
 		logger.SetShiftCondition(ShiftDayAndSize, 1, 5*MB)
-		fmt.Printf("(o *basicOption) SetOption | 5 MB: %d\n", 5*MB)
 	} else {
 		state &^= TOFILE
 	}
@@ -161,4 +163,274 @@ func (o *_debugOption) SetOption(logger *Logger) {
 	logger.outputs[DebugLevel] = TOCONSOLE | TOFILE | FILEINFO | LINEINFO
 	logger.outputs[InfoLevel] = TOCONSOLE | TOFILE | FILEINFO | LINEINFO
 	logger.SetShiftCondition(ShiftSecondAndSize, 30, 2*KB)
+	logger.SetConsoleEncoder(EncoderPretty)
+}
+
+type prettyConsoleOption struct {
+}
+
+// Console 改用開發用格式: 欄位對齊、相對時間、簡短的呼叫端、欄位逐行列出、錯誤與堆疊醒目標示
+// 輸出到檔案的格式不變
+func PrettyConsoleOption() *prettyConsoleOption {
+	o := &prettyConsoleOption{}
+	return o
+}
+
+func (o *prettyConsoleOption) SetOption(logger *Logger) {
+	logger.SetConsoleEncoder(EncoderPretty)
+}
+
+type stackTraceOption struct {
+	level LogLevel
+	depth int
+}
+
+// 等級大於等於 level 的 log，皆輸出呼叫端的堆疊，最多 depth 層
+func StackTraceOption(level LogLevel, depth int) *stackTraceOption {
+	o := &stackTraceOption{
+		level: level,
+		depth: depth,
+	}
+	return o
+}
+
+func (o *stackTraceOption) SetOption(logger *Logger) {
+	for level := range logger.outputs {
+		if level >= o.level {
+			logger.outputs[level] |= STACKTRACE
+		} else {
+			logger.outputs[level] &^= STACKTRACE
+		}
+	}
+
+	logger.SetStackDepth(o.depth)
+}
+
+type callerSkipOption struct {
+	skip int
+}
+
+// 設置定位呼叫端時額外略過 skip 層，用於專屬於包裝型別的 Logger，重複套用不會累加
+// 包裝共用的 Logger(例如 GetLogger 取得的)時，應改用 Logger.WithCallerSkip，以免影響其他呼叫端
+func AddCallerSkip(skip int) *callerSkipOption {
+	o := &callerSkipOption{
+		skip: skip,
+	}
+	return o
+}
+
+func (o *callerSkipOption) SetOption(logger *Logger) {
+	logger.SetCallerSkip(o.skip)
+}
+
+type callerPathOption struct {
+	mode CallerPathMode
+}
+
+// 設置輸出檔案資訊時，檔案路徑的呈現方式
+func CallerPathOption(mode CallerPathMode) *callerPathOption {
+	o := &callerPathOption{
+		mode: mode,
+	}
+	return o
+}
+
+func (o *callerPathOption) SetOption(logger *Logger) {
+	logger.SetCallerPathMode(o.mode)
+}
+
+type errorHandlerOption struct {
+	handler      ErrorHandler
+	fallback     FallbackPolicy
+	backupFolder string
+}
+
+// 設置發生內部錯誤時的處理函式，以及寫出檔案失敗時的補救方式
+func ErrorHandlerOption(handler ErrorHandler, fallback FallbackPolicy, backupFolder string) *errorHandlerOption {
+	o := &errorHandlerOption{
+		handler:      handler,
+		fallback:     fallback,
+		backupFolder: backupFolder,
+	}
+	return o
+}
+
+func (o *errorHandlerOption) SetOption(logger *Logger) {
+	logger.SetErrorHandler(o.handler)
+	logger.SetFallback(o.fallback, o.backupFolder)
+}
+
+type samplingOption struct {
+	interval   time.Duration
+	first      int
+	thereafter int
+}
+
+// 每 interval 內，同一類(相同等級與格式字串)的 log 只輸出前 first 筆，之後每 thereafter 筆輸出 1 筆
+func SamplingOption(interval time.Duration, first int, thereafter int) *samplingOption {
+	o := &samplingOption{
+		interval:   interval,
+		first:      first,
+		thereafter: thereafter,
+	}
+	return o
+}
+
+func (o *samplingOption) SetOption(logger *Logger) {
+	logger.SetSampling(o.interval, o.first, o.thereafter)
+}
+
+type rateLimitOption struct {
+	level     LogLevel
+	perSecond int
+	burst     int
+}
+
+// 限制 level 等級每秒最多輸出 perSecond 筆，可短暫累積至 burst 筆
+func RateLimitOption(level LogLevel, perSecond int, burst int) *rateLimitOption {
+	o := &rateLimitOption{
+		level:     level,
+		perSecond: perSecond,
+		burst:     burst,
+	}
+	return o
+}
+
+func (o *rateLimitOption) SetOption(logger *Logger) {
+	logger.SetRateLimit(o.level, o.perSecond, o.burst)
+}
+
+type dedupOption struct {
+	timeout time.Duration
+}
+
+// 合併同一呼叫端連續重複的訊息，重複次數最晚在 timeout 後輸出
+func DedupOption(timeout time.Duration) *dedupOption {
+	o := &dedupOption{
+		timeout: timeout,
+	}
+	return o
+}
+
+func (o *dedupOption) SetOption(logger *Logger) {
+	logger.SetDedup(o.timeout)
+}
+
+type hookOption struct {
+	hooks []Hook
+}
+
+// 註冊 Hook
+func HookOption(hooks ...Hook) *hookOption {
+	o := &hookOption{
+		hooks: hooks,
+	}
+	return o
+}
+
+func (o *hookOption) SetOption(logger *Logger) {
+	for _, hook := range o.hooks {
+		logger.AddHook(hook)
+	}
+}
+
+type redactOption struct {
+	redactor *Redactor
+}
+
+// 設置敏感資料遮蔽
+func RedactOption(redactor *Redactor) *redactOption {
+	o := &redactOption{
+		redactor: redactor,
+	}
+	return o
+}
+
+func (o *redactOption) SetOption(logger *Logger) {
+	logger.SetRedactor(o.redactor)
+}
+
+type colorOption struct {
+	mode    ColorMode
+	palette *Palette
+}
+
+// 設置 Console 輸出是否上色，以及所用的顏色，palette 為 nil 時使用預設顏色
+func ColorOption(mode ColorMode, palette *Palette) *colorOption {
+	o := &colorOption{
+		mode:    mode,
+		palette: palette,
+	}
+	return o
+}
+
+func (o *colorOption) SetOption(logger *Logger) {
+	logger.SetColorMode(o.mode)
+	logger.SetPalette(o.palette)
+}
+
+type consoleOption struct {
+	mode ConsoleMode
+}
+
+// 依預設組合設置 Console 輸出位置: ConsoleStdout, ConsoleStderr, ConsoleSplit
+func ConsoleOption(mode ConsoleMode) *consoleOption {
+	o := &consoleOption{
+		mode: mode,
+	}
+	return o
+}
+
+func (o *consoleOption) SetOption(logger *Logger) {
+	logger.SetConsole(o.mode)
+}
+
+type consoleWriterOption struct {
+	writer io.Writer
+	levels []LogLevel
+}
+
+// 設置 levels 輸出到 Console 的位置，未給定 levels 時套用到所有等級
+func ConsoleWriterOption(writer io.Writer, levels ...LogLevel) *consoleWriterOption {
+	o := &consoleWriterOption{
+		writer: writer,
+		levels: levels,
+	}
+	return o
+}
+
+func (o *consoleWriterOption) SetOption(logger *Logger) {
+	logger.SetConsoleWriter(o.writer, o.levels...)
+}
+
+type timeFormatOption struct {
+	format string
+}
+
+// 設置時間輸出格式，可為 time 的 layout，或 TimeUnix, TimeUnixMilli, TimeUnixNano
+func TimeFormatOption(format string) *timeFormatOption {
+	o := &timeFormatOption{
+		format: format,
+	}
+	return o
+}
+
+func (o *timeFormatOption) SetOption(logger *Logger) {
+	logger.SetTimeFormat(o.format)
+}
+
+type clockOption struct {
+	clock Clock
+}
+
+// 設置時間來源，測試時可替換為固定或可控的時間
+func ClockOption(clock Clock) *clockOption {
+	o := &clockOption{
+		clock: clock,
+	}
+	return o
+}
+
+func (o *clockOption) SetOption(logger *Logger) {
+	logger.SetClock(o.clock)
 }
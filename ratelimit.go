@@ -0,0 +1,118 @@
+package glog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 令牌桶，每秒補充 rate 個令牌，最多累積 burst 個
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	// 上次補充令牌的時間
+	last time.Time
+	// 因超過限制而被略過的數量，以及開始略過的時間
+	suppressed uint64
+	since      time.Time
+}
+
+// 依等級限制輸出速率，未設置限制的等級不受影響
+type rateLimiter struct {
+	buckets map[LogLevel]*tokenBucket
+	mu      sync.Mutex
+}
+
+// 設置 level 等級每秒最多輸出 perSecond 筆，可短暫累積至 burst 筆
+// perSecond 小於等於 0 時，取消該等級的限制
+// 超過限制的 log 會被略過，並在之後第一筆輸出的 log 之前或 Close 時，輸出略過的數量
+func (l *Logger) SetRateLimit(level LogLevel, perSecond int, burst int) {
+	if l.limiter == nil {
+		l.limiter = &rateLimiter{
+			buckets: map[LogLevel]*tokenBucket{},
+		}
+	}
+
+	l.limiter.mu.Lock()
+	defer l.limiter.mu.Unlock()
+
+	if perSecond <= 0 {
+		delete(l.limiter.buckets, level)
+		return
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.limiter.buckets[level] = &tokenBucket{
+		rate:   float64(perSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   l.getTime(),
+	}
+}
+
+// 檢查該筆 log 是否輸出，若先前有被略過的 log，一併回傳略過數量的統計訊息
+func (r *rateLimiter) check(level LogLevel, now time.Time) (bool, *entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bucket, ok := r.buckets[level]
+
+	if !ok {
+		return true, nil
+	}
+
+	if elapsed := now.Sub(bucket.last).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * bucket.rate
+
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+	}
+
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		if bucket.suppressed == 0 {
+			bucket.since = now
+		}
+
+		bucket.suppressed++
+		return false, nil
+	}
+
+	bucket.tokens--
+	return true, bucket.report(level, now)
+}
+
+// 取出各等級尚未輸出的略過數量統計訊息
+func (r *rateLimiter) flush(now time.Time) []*entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := []*entry{}
+
+	for level, bucket := range r.buckets {
+		if report := bucket.report(level, now); report != nil {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+// 將略過數量轉為統計訊息並歸零，呼叫前需持有 rateLimiter 的 mu
+func (b *tokenBucket) report(level LogLevel, now time.Time) *entry {
+	if b.suppressed == 0 {
+		return nil
+	}
+
+	report := &entry{
+		level:   level,
+		message: fmt.Sprintf("glog: suppressed %d messages in last %.3fs", b.suppressed, now.Sub(b.since).Seconds()),
+		summary: true,
+	}
+	b.suppressed = 0
+	return report
+}
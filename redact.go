@@ -0,0 +1,193 @@
+package glog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ====================================================================================================
+// MaskStrategy
+// ====================================================================================================
+// 敏感資料的遮蔽方式
+type MaskStrategy byte
+
+const (
+	// 完全遮蔽: ****
+	MaskFull MaskStrategy = iota
+	// 只保留最後 4 個字元: ************1234
+	MaskPartial
+	// 以 SHA-256 的前 12 碼取代，相同的值會得到相同的結果，便於關聯: sha256:9f86d081884c
+	MaskHash
+)
+
+func (s MaskStrategy) String() string {
+	switch s {
+	case MaskFull:
+		return "MaskFull"
+	case MaskPartial:
+		return "MaskPartial"
+	case MaskHash:
+		return "MaskHash"
+	default:
+		return "Unknown"
+	}
+}
+
+func (s MaskStrategy) mask(text string) string {
+	switch s {
+	case MaskPartial:
+		runes := []rune(text)
+
+		if len(runes) <= 4 {
+			return "****"
+		}
+
+		return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+	case MaskHash:
+		sum := sha256.Sum256([]byte(text))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	default:
+		return "****"
+	}
+}
+
+// 常見的敏感資料格式
+var (
+	// 13 至 19 位數的卡號，數字之間可有空白或 '-'
+	PatternCreditCard = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	PatternEmail      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	PatternJWT        = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+)
+
+type redactPattern struct {
+	pattern  *regexp.Regexp
+	strategy MaskStrategy
+}
+
+// 敏感資料遮蔽: 遮蔽指定 key 的欄位值，以及訊息與欄位值中符合正規表示式的內容
+type Redactor struct {
+	// 欄位 key(不分大小寫)與其遮蔽方式
+	keys     map[string]MaskStrategy
+	patterns []redactPattern
+	mu       sync.RWMutex
+}
+
+func NewRedactor() *Redactor {
+	r := &Redactor{
+		keys:     map[string]MaskStrategy{},
+		patterns: []redactPattern{},
+	}
+	return r
+}
+
+// 預設的遮蔽設定: 完全遮蔽 password, secret, token, authorization, api_key 欄位，
+// 並遮蔽訊息中的卡號(保留末 4 碼)、email(雜湊)與 JWT(完全遮蔽)
+func DefaultRedactor() *Redactor {
+	r := NewRedactor()
+	r.AddKeys(MaskFull, "password", "secret", "token", "authorization", "api_key")
+	r.AddPattern(MaskPartial, PatternCreditCard)
+	r.AddPattern(MaskHash, PatternEmail)
+	r.AddPattern(MaskFull, PatternJWT)
+	return r
+}
+
+// 遮蔽指定 key 的欄位值，key 不分大小寫
+func (r *Redactor) AddKeys(strategy MaskStrategy, keys ...string) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		r.keys[strings.ToLower(key)] = strategy
+	}
+
+	return r
+}
+
+// 遮蔽訊息與欄位值中，符合 pattern 的內容
+func (r *Redactor) AddPattern(strategy MaskStrategy, pattern *regexp.Regexp) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, redactPattern{pattern: pattern, strategy: strategy})
+	return r
+}
+
+// 回傳遮蔽後的訊息與欄位，不會修改傳入的欄位(可能來自 context，為多筆 log 共用)
+func (r *Redactor) redact(message string, fields []Field) (string, []Field) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	message = r.redactText(message)
+
+	if len(fields) == 0 {
+		return message, fields
+	}
+
+	results := make([]Field, len(fields))
+
+	for i, field := range fields {
+		results[i] = r.redactField(field)
+	}
+
+	return message, results
+}
+
+// 遮蔽指定 key 的欄位值，或欄位值格式化後符合正規表示式的內容，呼叫前需持有 mu
+// error、fmt.Stringer、struct 等非字串的值，以輸出時的文字比對，有遮蔽時改以遮蔽後的字串輸出
+func (r *Redactor) redactField(field Field) Field {
+	text := field.valueString()
+
+	if strategy, ok := r.keys[strings.ToLower(field.Key)]; ok {
+		return NewField(field.Key, strategy.mask(text))
+	}
+
+	if redacted := r.redactText(text); redacted != text {
+		return NewField(field.Key, redacted)
+	}
+
+	return field
+}
+
+// 只遮蔽與 before 不同的訊息與欄位，before 為已遮蔽過的內容
+// 已遮蔽的內容不再重複處理，以免 MaskHash 對雜湊值再次雜湊
+func (r *Redactor) redactChanged(beforeMessage string, beforeFields []Field, message string, fields []Field) (string, []Field) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if message != beforeMessage {
+		message = r.redactText(message)
+	}
+
+	// 以 key=value 記錄已遮蔽過的欄位，相同的欄位可出現多次
+	redacted := map[string]int{}
+
+	for _, field := range beforeFields {
+		redacted[field.String()]++
+	}
+
+	results := make([]Field, len(fields))
+
+	for i, field := range fields {
+		if text := field.String(); redacted[text] > 0 {
+			redacted[text]--
+			results[i] = field
+		} else {
+			results[i] = r.redactField(field)
+		}
+	}
+
+	return message, results
+}
+
+func (r *Redactor) redactText(text string) string {
+	for _, p := range r.patterns {
+		text = p.pattern.ReplaceAllStringFunc(text, p.strategy.mask)
+	}
+	return text
+}
+
+// 設置敏感資料遮蔽，設為 nil 則不遮蔽
+func (l *Logger) SetRedactor(redactor *Redactor) {
+	l.redactor = redactor
+}
@@ -0,0 +1,119 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaskStrategy(t *testing.T) {
+	tests := []struct {
+		strategy MaskStrategy
+		text     string
+		want     string
+	}{
+		{MaskFull, "hunter2", "****"},
+		{MaskPartial, "4111111111111234", "************1234"},
+		{MaskPartial, "abc", "****"},
+		{MaskHash, "test", "sha256:9f86d081884c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			if got := tt.strategy.mask(tt.text); got != tt.want {
+				t.Errorf("mask(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+type emailStringer struct{}
+
+func (emailStringer) String() string {
+	return "owner alice@example.com"
+}
+
+type cardHolder struct {
+	Card string
+}
+
+func TestRedact(t *testing.T) {
+	emailHash := MaskHash.mask("alice@example.com")
+
+	tests := []struct {
+		name     string
+		log      func(l *Logger)
+		want     []string
+		unwanted []string
+	}{
+		{"key full", func(l *Logger) {
+			l.InfoCtx(WithContext(context.Background(), NewField("Password", "hunter2")), "login")
+		}, []string{"Password=****"}, []string{"hunter2"}},
+		{"key hash", func(l *Logger) {
+			l.InfoCtx(WithContext(context.Background(), NewField("session", "abc")), "login")
+		}, []string{"session=" + MaskHash.mask("abc")}, []string{"=abc"}},
+		{"message patterns", func(l *Logger) {
+			l.Info("mail alice@example.com card 4111 1111 1111 1234 jwt eyJa.eyJb.c")
+		}, []string{emailHash, "card ***************1234", "jwt ****"}, []string{"alice@", "eyJa"}},
+		{"error value", func(l *Logger) {
+			l.Err(errors.New("bad jwt eyJa.eyJb.c"), "failed")
+		}, []string{"bad jwt ****"}, []string{"eyJa"}},
+		{"stringer value", func(l *Logger) {
+			l.InfoCtx(WithContext(context.Background(), NewField("owner", emailStringer{})), "lookup")
+		}, []string{"owner=owner " + emailHash}, []string{"alice@"}},
+		{"struct value", func(l *Logger) {
+			l.InfoCtx(WithContext(context.Background(), NewField("holder", cardHolder{Card: "4111111111111234"})), "charge")
+		}, []string{"************1234"}, []string{"4111111111111234"}},
+		{"hook fields", func(l *Logger) {
+			l.AddHook(NewHook(func(entry *Entry) error {
+				entry.Message += " by bob@example.com"
+				entry.Fields = append(entry.Fields, NewField("token", "abc"), NewField("note", "alice@example.com"))
+				return nil
+			}))
+			l.InfoCtx(WithContext(context.Background(), NewField("session", "abc")), "login alice@example.com")
+		}, []string{
+			"login " + emailHash + " by " + MaskHash.mask("bob@example.com"),
+			// 已遮蔽的欄位不可再次雜湊
+			"session=" + MaskHash.mask("abc"),
+			"token=****",
+			"note=" + emailHash,
+		}, []string{"@example.com", "token=abc"}},
+		{"summary line", func(l *Logger) {
+			l.SetSampling(time.Minute, 1, 0)
+
+			for i := 0; i < 3; i++ {
+				l.Info("login alice@example.com")
+			}
+
+			l.Close()
+		}, []string{"sampled out 2 entries of \"login " + emailHash + "\""}, []string{"alice@"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			l := newLogger("redact", DebugLevel)
+			l.SetOptions(
+				ConsoleWriterOption(buffer),
+				RedactOption(DefaultRedactor().AddKeys(MaskHash, "session")),
+			)
+			tt.log(l)
+			output := buffer.String()
+
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output does not contain %q, output: %q", want, output)
+				}
+			}
+
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("output contains %q, output: %q", unwanted, output)
+				}
+			}
+		})
+	}
+}
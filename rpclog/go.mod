@@ -0,0 +1,20 @@
+module github.com/j32u4ukh/glog/rpclog
+
+go 1.21
+
+require (
+	github.com/j32u4ukh/glog v0.0.0
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
+
+// glog 尚未發佈版本前，使用同一個 repo 中的原始碼
+replace github.com/j32u4ukh/glog => ../
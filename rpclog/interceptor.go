@@ -0,0 +1,290 @@
+// rpclog 提供 gRPC 的伺服器端與用戶端攔截器，將每個 RPC 的資訊經由 *glog.Logger 輸出
+// 為了不讓 glog 本身依賴 gRPC，此套件為獨立的 module
+package rpclog
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/j32u4ukh/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// 伺服器端 Unary 攔截器，輸出 method, peer, code, duration，以及(可選的)請求與回應大小
+func UnaryServerInterceptor(logger *glog.Logger, options ...Option) grpc.UnaryServerInterceptor {
+	c := buildConfig(options)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		fields := []glog.Field{
+			glog.NewField("method", info.FullMethod),
+			glog.NewField("peer", peerAddress(ctx)),
+		}
+
+		if c.payloadSize {
+			fields = append(fields,
+				glog.NewField("request_size", messageSize(req)),
+				glog.NewField("response_size", messageSize(resp)),
+			)
+		}
+
+		c.logout(logger, ctx, "grpc server", start, err, fields)
+		return resp, err
+	}
+}
+
+// 伺服器端 Stream 攔截器，請求與回應大小為整個 stream 的累計值
+func StreamServerInterceptor(logger *glog.Logger, options ...Option) grpc.StreamServerInterceptor {
+	c := buildConfig(options)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		stream := &serverStream{ServerStream: ss, payloadSize: c.payloadSize}
+		err := handler(srv, stream)
+		ctx := ss.Context()
+		fields := []glog.Field{
+			glog.NewField("method", info.FullMethod),
+			glog.NewField("peer", peerAddress(ctx)),
+		}
+
+		if c.payloadSize {
+			fields = append(fields,
+				glog.NewField("request_size", atomic.LoadInt64(&stream.received)),
+				glog.NewField("response_size", atomic.LoadInt64(&stream.sent)),
+			)
+		}
+
+		c.logout(logger, ctx, "grpc server", start, err, fields)
+		return err
+	}
+}
+
+// 用戶端 Unary 攔截器
+func UnaryClientInterceptor(logger *glog.Logger, options ...Option) grpc.UnaryClientInterceptor {
+	c := buildConfig(options)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		// 實際連線的對象，cc.Target() 僅為撥號時的目標
+		p := &peer.Peer{}
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(p))...)
+		fields := []glog.Field{
+			glog.NewField("method", method),
+			glog.NewField("peer", addrString(p)),
+		}
+
+		if c.payloadSize {
+			fields = append(fields,
+				glog.NewField("request_size", messageSize(req)),
+				glog.NewField("response_size", messageSize(reply)),
+			)
+		}
+
+		c.logout(logger, ctx, "grpc client", start, err, fields)
+		return err
+	}
+}
+
+// 用戶端 Stream 攔截器，於 stream 結束(RecvMsg 回傳 io.EOF 或錯誤)時輸出，請求與回應大小為整個 stream 的累計值
+// 建立 stream 失敗時立即輸出；未讀取至結束的 stream，於 ctx 被取消或逾時時輸出
+// 與 gRPC 本身的要求相同，呼叫端需讀取至結束或取消 ctx，否則該 stream 不會被輸出
+func StreamClientInterceptor(logger *glog.Logger, options ...Option) grpc.StreamClientInterceptor {
+	c := buildConfig(options)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		fields := []glog.Field{
+			glog.NewField("method", method),
+		}
+
+		if err != nil {
+			fields = append(fields, glog.NewField("peer", ""))
+			c.logout(logger, ctx, "grpc client stream", start, err, fields)
+			return stream, err
+		}
+
+		s := &clientStream{
+			ClientStream:  stream,
+			config:        c,
+			logger:        logger,
+			ctx:           ctx,
+			start:         start,
+			fields:        fields,
+			serverStreams: desc.ServerStreams,
+			done:          make(chan struct{}),
+		}
+		go s.watch()
+		return s, nil
+	}
+}
+
+func buildConfig(options []Option) *config {
+	c := newConfig()
+
+	for _, option := range options {
+		option.SetOption(c)
+	}
+
+	return c
+}
+
+func (c *config) logout(logger *glog.Logger, ctx context.Context, message string, start time.Time, err error, fields []glog.Field) {
+	code := status.Code(err)
+	fields = append(fields,
+		glog.NewField("code", code.String()),
+		glog.NewField("duration", time.Since(start)),
+	)
+
+	if err != nil {
+		fields = append(fields, glog.NewField("error", err.Error()))
+	}
+
+	ctx = glog.WithContext(ctx, fields...)
+
+	switch c.codeToLevel(code) {
+	case glog.DebugLevel:
+		logger.DebugCtx(ctx, message)
+	case glog.InfoLevel:
+		logger.InfoCtx(ctx, message)
+	case glog.WarnLevel:
+		logger.WarnCtx(ctx, message)
+	default:
+		logger.ErrorCtx(ctx, message)
+	}
+}
+
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return addrString(p)
+	}
+	return ""
+}
+
+func addrString(p *peer.Peer) string {
+	if p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+func messageSize(message any) int {
+	if m, ok := message.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// 累計 stream 中收送訊息的大小
+type serverStream struct {
+	grpc.ServerStream
+	payloadSize bool
+	sent        int64
+	received    int64
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+
+	if err == nil && s.payloadSize {
+		atomic.AddInt64(&s.sent, int64(messageSize(m)))
+	}
+
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+
+	if err == nil && s.payloadSize {
+		atomic.AddInt64(&s.received, int64(messageSize(m)))
+	}
+
+	return err
+}
+
+// 累計 stream 中收送訊息的大小，並於 stream 結束時輸出一次
+type clientStream struct {
+	grpc.ClientStream
+	config *config
+	logger *glog.Logger
+	ctx    context.Context
+	start  time.Time
+	fields []glog.Field
+	// 伺服器端是否會回傳多個訊息
+	serverStreams bool
+	sent          int64
+	received      int64
+	once          sync.Once
+	// stream 結束並輸出後關閉
+	done chan struct{}
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+
+	if err == nil && s.config.payloadSize {
+		atomic.AddInt64(&s.sent, int64(messageSize(m)))
+	}
+
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	if err == nil {
+		if s.config.payloadSize {
+			atomic.AddInt64(&s.received, int64(messageSize(m)))
+		}
+
+		// 伺服器端只回傳一個訊息時，成功收到即代表 RPC 結束
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+
+		return nil
+	}
+
+	// io.EOF 代表 stream 正常結束
+	if err == io.EOF {
+		s.finish(nil)
+	} else {
+		s.finish(err)
+	}
+
+	return err
+}
+
+// 未讀取至結束的 stream，於 ctx 被取消或逾時時輸出
+func (s *clientStream) watch() {
+	select {
+	case <-s.ctx.Done():
+		s.finish(status.FromContextError(s.ctx.Err()).Err())
+	case <-s.done:
+	}
+}
+
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		defer close(s.done)
+		// stream 結束後才取得實際連線的對象，避免影響 gRPC 的重試
+		fields := append(s.fields, glog.NewField("peer", peerAddress(s.ClientStream.Context())))
+
+		if s.config.payloadSize {
+			fields = append(fields,
+				glog.NewField("request_size", atomic.LoadInt64(&s.sent)),
+				glog.NewField("response_size", atomic.LoadInt64(&s.received)),
+			)
+		}
+
+		s.config.logout(s.logger, s.ctx, "grpc client stream", s.start, err, fields)
+	})
+}
@@ -0,0 +1,370 @@
+package rpclog
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/j32u4ukh/glog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	echoMethod   = "/rpclog.test.Echo/Echo"
+	repeatMethod = "/rpclog.test.Echo/Repeat"
+	// bufconn 連線的位址
+	bufconnAddr = "bufconn"
+)
+
+// 依訊息內容回傳對應的錯誤: "fail" 為 NotFound，"boom" 為 Internal
+func echoError(value string) error {
+	switch value {
+	case "fail":
+		return status.Error(codes.NotFound, "not found")
+	case "boom":
+		return status.Error(codes.Internal, "internal")
+	default:
+		return nil
+	}
+}
+
+// 原樣回傳收到的訊息
+func echoHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	request := &wrapperspb.StringValue{}
+
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		request := req.(*wrapperspb.StringValue)
+
+		if err := echoError(request.Value); err != nil {
+			return nil, err
+		}
+
+		return request, nil
+	}
+
+	if interceptor == nil {
+		return handler(ctx, request)
+	}
+
+	return interceptor(ctx, request, &grpc.UnaryServerInfo{Server: srv, FullMethod: echoMethod}, handler)
+}
+
+// 收到一個訊息後回傳三次，"hang" 時不回傳並等待用戶端取消
+func repeatHandler(srv any, stream grpc.ServerStream) error {
+	request := &wrapperspb.StringValue{}
+
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+
+	if request.Value == "hang" {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}
+
+	if err := echoError(request.Value); err != nil {
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := stream.SendMsg(request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rpclog.test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Echo", Handler: echoHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Repeat", Handler: repeatHandler, ServerStreams: true, ClientStreams: true},
+	},
+}
+
+// 記錄每筆 log 的 Hook
+type recordHook struct {
+	mu      sync.Mutex
+	entries []glog.Entry
+}
+
+func (h *recordHook) Levels() []glog.LogLevel {
+	return []glog.LogLevel{glog.DebugLevel, glog.InfoLevel, glog.WarnLevel, glog.ErrorLevel}
+}
+
+func (h *recordHook) Fire(entry *glog.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, *entry)
+	return nil
+}
+
+func (h *recordHook) take() []glog.Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := h.entries
+	h.entries = nil
+	return entries
+}
+
+// 等待至少一筆 log，用於在其他 goroutine 輸出的情形
+func (h *recordHook) wait(t *testing.T) []glog.Entry {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		h.mu.Lock()
+		n := len(h.entries)
+		h.mu.Unlock()
+
+		if n > 0 {
+			return h.take()
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("no entry was logged")
+	return nil
+}
+
+func newTestLogger(t *testing.T, idx byte) (*glog.Logger, *recordHook) {
+	hook := &recordHook{}
+	logger := glog.SetLogger(idx, "rpclog", glog.DebugLevel)
+	logger.SetConsoleWriter(io.Discard)
+	logger.AddHook(hook)
+	t.Cleanup(func() { glog.RemoveLogger(idx) })
+	return logger, hook
+}
+
+type testEnv struct {
+	cc         *grpc.ClientConn
+	serverHook *recordHook
+	clientHook *recordHook
+}
+
+// 建立以 bufconn 連線的伺服器端與用戶端，兩端皆安裝攔截器
+func newTestEnv(t *testing.T) *testEnv {
+	serverLogger, serverHook := newTestLogger(t, 0)
+	clientLogger, clientHook := newTestLogger(t, 1)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverLogger, PayloadSizeOption(true))),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverLogger, PayloadSizeOption(true))),
+	)
+	server.RegisterService(&echoServiceDesc, struct{}{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(clientLogger, PayloadSizeOption(true))),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(clientLogger, PayloadSizeOption(true))),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { cc.Close() })
+	return &testEnv{cc: cc, serverHook: serverHook, clientHook: clientHook}
+}
+
+func fieldValue(entry glog.Entry, key string) any {
+	for _, field := range entry.Fields {
+		if field.Key == key {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+type expectation struct {
+	method       string
+	code         codes.Code
+	level        glog.LogLevel
+	requestSize  any
+	responseSize any
+}
+
+// 檢查恰好輸出一筆 log，且內容符合預期
+func checkEntries(t *testing.T, side string, entries []glog.Entry, want expectation) {
+	t.Helper()
+
+	if len(entries) != 1 {
+		t.Fatalf("%s logged %d entries, want 1", side, len(entries))
+	}
+
+	entry := entries[0]
+
+	if entry.Level != want.level {
+		t.Errorf("%s level = %v, want %v", side, entry.Level, want.level)
+	}
+
+	checks := []struct {
+		key  string
+		want any
+	}{
+		{"method", want.method},
+		{"peer", bufconnAddr},
+		{"code", want.code.String()},
+		{"request_size", want.requestSize},
+		{"response_size", want.responseSize},
+	}
+
+	for _, check := range checks {
+		if value := fieldValue(entry, check.key); value != check.want {
+			t.Errorf("%s %s = %v (%T), want %v (%T)", side, check.key, value, value, check.want, check.want)
+		}
+	}
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	env := newTestEnv(t)
+	size := func(value string) int { return proto.Size(wrapperspb.String(value)) }
+
+	tests := []struct {
+		value        string
+		code         codes.Code
+		level        glog.LogLevel
+		responseSize int
+	}{
+		{"hello", codes.OK, glog.InfoLevel, size("hello")},
+		{"fail", codes.NotFound, glog.WarnLevel, 0},
+		{"boom", codes.Internal, glog.ErrorLevel, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			reply := &wrapperspb.StringValue{}
+			err := env.cc.Invoke(context.Background(), echoMethod, wrapperspb.String(tt.value), reply)
+
+			if status.Code(err) != tt.code {
+				t.Fatalf("Invoke returned %v, want code %v", err, tt.code)
+			}
+
+			want := expectation{
+				method:       echoMethod,
+				code:         tt.code,
+				level:        tt.level,
+				requestSize:  size(tt.value),
+				responseSize: tt.responseSize,
+			}
+			checkEntries(t, "server", env.serverHook.take(), want)
+			checkEntries(t, "client", env.clientHook.take(), want)
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	env := newTestEnv(t)
+	size := func(value string) int64 { return int64(proto.Size(wrapperspb.String(value))) }
+
+	tests := []struct {
+		value        string
+		code         codes.Code
+		level        glog.LogLevel
+		responseSize int64
+	}{
+		{"hello", codes.OK, glog.InfoLevel, 3 * size("hello")},
+		{"fail", codes.NotFound, glog.WarnLevel, 0},
+		{"boom", codes.Internal, glog.ErrorLevel, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			stream, err := env.cc.NewStream(context.Background(), &echoServiceDesc.Streams[0], repeatMethod)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = stream.SendMsg(wrapperspb.String(tt.value)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = stream.CloseSend(); err != nil {
+				t.Fatal(err)
+			}
+
+			for err == nil {
+				err = stream.RecvMsg(&wrapperspb.StringValue{})
+			}
+
+			if status.Code(err) != tt.code && !(tt.code == codes.OK && err == io.EOF) {
+				t.Fatalf("RecvMsg returned %v, want code %v", err, tt.code)
+			}
+
+			// 結束後再次呼叫 RecvMsg 不應重複輸出
+			stream.RecvMsg(&wrapperspb.StringValue{})
+
+			want := expectation{
+				method:       repeatMethod,
+				code:         tt.code,
+				level:        tt.level,
+				requestSize:  size(tt.value),
+				responseSize: tt.responseSize,
+			}
+			checkEntries(t, "server", env.serverHook.take(), want)
+			checkEntries(t, "client", env.clientHook.take(), want)
+		})
+	}
+}
+
+// 未讀取至結束的 stream，於 ctx 被取消時輸出
+func TestStreamClientInterceptorCanceled(t *testing.T) {
+	env := newTestEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := env.cc.NewStream(ctx, &echoServiceDesc.Streams[0], repeatMethod)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = stream.SendMsg(wrapperspb.String("hang")); err != nil {
+		t.Fatal(err)
+	}
+
+	// stream 結束前不應輸出
+	time.Sleep(10 * time.Millisecond)
+
+	if entries := env.clientHook.take(); len(entries) != 0 {
+		t.Fatalf("logged %d entries before the stream ended", len(entries))
+	}
+
+	cancel()
+	entries := env.clientHook.wait(t)
+
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+
+	if code := fieldValue(entries[0], "code"); code != codes.Canceled.String() {
+		t.Errorf("code = %v, want %v", code, codes.Canceled)
+	}
+
+	if entries[0].Level != glog.WarnLevel {
+		t.Errorf("level = %v, want %v", entries[0].Level, glog.WarnLevel)
+	}
+}
@@ -0,0 +1,73 @@
+package rpclog
+
+import (
+	"github.com/j32u4ukh/glog"
+	"google.golang.org/grpc/codes"
+)
+
+type Option interface {
+	SetOption(*config)
+}
+
+// 將 gRPC 狀態碼轉換為輸出等級
+type CodeToLevel func(code codes.Code) glog.LogLevel
+
+type config struct {
+	codeToLevel CodeToLevel
+	// 是否輸出請求與回應的大小
+	payloadSize bool
+}
+
+func newConfig() *config {
+	c := &config{
+		codeToLevel: DefaultCodeToLevel,
+		payloadSize: false,
+	}
+	return c
+}
+
+// 預設的狀態碼轉換: 成功為 Info，用戶端造成的錯誤為 Warn，伺服器端的錯誤為 Error
+func DefaultCodeToLevel(code codes.Code) glog.LogLevel {
+	switch code {
+	case codes.OK:
+		return glog.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return glog.WarnLevel
+	default:
+		return glog.ErrorLevel
+	}
+}
+
+type levelOption struct {
+	codeToLevel CodeToLevel
+}
+
+// 自訂 gRPC 狀態碼與輸出等級的對應
+func LevelOption(codeToLevel CodeToLevel) *levelOption {
+	o := &levelOption{
+		codeToLevel: codeToLevel,
+	}
+	return o
+}
+
+func (o *levelOption) SetOption(c *config) {
+	c.codeToLevel = o.codeToLevel
+}
+
+type payloadSizeOption struct {
+	enable bool
+}
+
+// 是否輸出請求與回應的大小(僅計算 proto.Message)
+func PayloadSizeOption(enable bool) *payloadSizeOption {
+	o := &payloadSizeOption{
+		enable: enable,
+	}
+	return o
+}
+
+func (o *payloadSizeOption) SetOption(c *config) {
+	c.payloadSize = o.enable
+}
@@ -0,0 +1,122 @@
+package glog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 抽樣的 key，相同等級與相同格式字串的 log 視為同一類
+type sampleKey struct {
+	level    LogLevel
+	template string
+}
+
+type sampleCount struct {
+	// 當前區間內的數量
+	total uint64
+	// 當前區間內被略過的數量
+	dropped uint64
+}
+
+// 每個區間內，同一類 log 只輸出前 first 筆，之後每 thereafter 筆輸出 1 筆
+// 區間結束時，以被略過的 log 的等級輸出略過的數量
+type sampler struct {
+	interval   time.Duration
+	first      uint64
+	thereafter uint64
+	// 當前區間的結束時間
+	windowEnd time.Time
+	counts    map[sampleKey]*sampleCount
+	mu        sync.Mutex
+}
+
+func newSampler(interval time.Duration, first int, thereafter int) *sampler {
+	s := &sampler{
+		interval:   interval,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		counts:     map[sampleKey]*sampleCount{},
+	}
+	return s
+}
+
+// 設置抽樣: 每 interval 內，同一類 log 只輸出前 first 筆，之後每 thereafter 筆輸出 1 筆
+// thereafter 小於等於 0 時，超過 first 筆後全部略過；interval 小於等於 0 時取消抽樣
+// 被略過的數量，在下一個區間的第一筆 log 之前或 Close 時輸出
+func (l *Logger) SetSampling(interval time.Duration, first int, thereafter int) {
+	if interval <= 0 {
+		l.sampler = nil
+		return
+	}
+
+	if first < 0 {
+		first = 0
+	}
+
+	if thereafter < 0 {
+		thereafter = 0
+	}
+
+	l.sampler = newSampler(interval, first, thereafter)
+}
+
+// 檢查該筆 log 是否輸出，若前一個區間已結束，一併回傳其略過數量的統計訊息
+func (s *sampler) check(level LogLevel, template string, now time.Time) (bool, []*entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var reports []*entry
+
+	if now.After(s.windowEnd) {
+		reports = s.reset(now)
+	}
+
+	key := sampleKey{level: level, template: template}
+	count, ok := s.counts[key]
+
+	if !ok {
+		count = &sampleCount{}
+		s.counts[key] = count
+	}
+
+	count.total++
+
+	if count.total <= s.first {
+		return true, reports
+	}
+
+	if s.thereafter > 0 && (count.total-s.first)%s.thereafter == 0 {
+		return true, reports
+	}
+
+	count.dropped++
+	return false, reports
+}
+
+// 取出當前區間尚未輸出的略過數量統計訊息，並開始新的區間
+func (s *sampler) flush(now time.Time) []*entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reset(now)
+}
+
+// 開始新的區間，並將前一個區間的略過數量轉為統計訊息
+func (s *sampler) reset(now time.Time) []*entry {
+	reports := []*entry{}
+
+	for key, count := range s.counts {
+		if count.dropped == 0 {
+			continue
+		}
+
+		reports = append(reports, &entry{
+			level:   key.level,
+			message: fmt.Sprintf("glog: sampled out %d entries of %q in last %s", count.dropped, key.template, s.interval),
+			summary: true,
+		})
+	}
+
+	s.counts = map[sampleKey]*sampleCount{}
+	s.windowEnd = now.Add(s.interval)
+	return reports
+}
@@ -0,0 +1,51 @@
+package glog
+
+import (
+	"bytes"
+	"log"
+)
+
+// 標準函式庫 log 的輸出，經由 log.Printf 等函式呼叫 Write 時，呼叫端位於 logout 往上第 4 層
+// 0: logout, 1: stdLogWriter.Write, 2: log.(*Logger).output, 3: log.Printf, 4: 呼叫端
+const stdLogSkip int = 4
+
+// 將標準函式庫 log 的每一行輸出，轉交給 Logger 以指定等級輸出
+type stdLogWriter struct {
+	logger *Logger
+	level  LogLevel
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\r\n"), []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+
+		if len(line) == 0 {
+			continue
+		}
+
+		if err := w.logger.logout(stdLogSkip, &entry{level: w.level, message: string(line)}); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// 將標準函式庫 log 的輸出導向 logger，並以 level 等級輸出
+// 時間與前綴由 glog 負責，因此會暫時清除 log 的 flags 與 prefix
+// 回傳的函式可將 log 還原為導向前的設定
+func RedirectStdLog(logger *Logger, level LogLevel) func() {
+	writer, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdLogWriter{
+		logger: logger,
+		level:  level,
+	})
+
+	return func() {
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
@@ -0,0 +1,101 @@
+package glog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// 單行的長度上限，超過時先將已寫入的部分作為一行輸出，避免沒有換行的數據(例如二進位輸出)使緩衝無限增長
+const writerLineLimit int = 64 * 1024
+
+// 將寫入的數據依換行切分，每一行作為一筆 log 以指定等級輸出
+// 呼叫 Write 的通常是 fmt、io、os/exec 等函式，並非訊息的來源，因此不輸出呼叫端
+type levelWriter struct {
+	logger *Logger
+	level  LogLevel
+	// 尚未遇到換行的數據
+	buffer []byte
+	closed bool
+	mu     sync.Mutex
+}
+
+// 取得以 level 等級輸出的 io.WriteCloser，可用於 exec.Cmd.Stdout、http.Server.ErrorLog 等
+// 最後一行若沒有換行，會在 Close 時輸出；單行超過 64KB 時會切分為多行輸出
+func (l *Logger) Writer(level LogLevel) io.WriteCloser {
+	w := &levelWriter{
+		logger: l,
+		level:  level,
+		buffer: []byte{},
+		closed: false,
+	}
+	return w
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	w.buffer = append(w.buffer, p...)
+	var idx int
+
+	for {
+		idx = bytes.IndexByte(w.buffer, '\n')
+
+		if idx < 0 {
+			break
+		}
+
+		err := w.emit(w.buffer[:idx])
+		w.buffer = w.buffer[idx+1:]
+
+		if err != nil {
+			return len(p), err
+		}
+	}
+
+	for len(w.buffer) >= writerLineLimit {
+		err := w.emit(w.buffer[:writerLineLimit])
+		w.buffer = w.buffer[writerLineLimit:]
+
+		if err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	var err error
+
+	if len(w.buffer) > 0 {
+		err = w.emit(w.buffer)
+		w.buffer = nil
+	}
+
+	return err
+}
+
+func (w *levelWriter) emit(line []byte) error {
+	line = bytes.TrimRight(line, "\r")
+
+	if len(line) == 0 {
+		return nil
+	}
+
+	return w.logger.logout(0, &entry{level: w.level, message: string(line), noCaller: true})
+}
@@ -0,0 +1,56 @@
+package glog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 經由 Writer 輸出的內容不應帶有呼叫 Write 的函式資訊
+func TestWriterWithoutCaller(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := newLogger("writer", DebugLevel)
+	l.SetOptions(ConsoleWriterOption(buffer), StackTraceOption(WarnLevel, 8))
+	w := l.Writer(WarnLevel)
+	fmt.Fprintf(w, "a\n")
+	w.Close()
+	output := buffer.String()
+
+	if !strings.Contains(output, "| a") {
+		t.Fatalf("line was not logged, output: %q", output)
+	}
+
+	for _, unwanted := range []string{"[fmt]", ".go:", "Fprintf"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("output contains %q, output: %q", unwanted, output)
+		}
+	}
+}
+
+// 沒有換行的數據超過長度上限時，需切分輸出，不可無限累積
+func TestWriterLineLimit(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := newLogger("writer", DebugLevel)
+	l.SetOptions(ConsoleWriterOption(buffer))
+	w := l.Writer(InfoLevel).(*levelWriter)
+	data := bytes.Repeat([]byte("x"), 2*writerLineLimit+10)
+
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(buffer.String(), "\n"); n != 2 {
+		t.Errorf("logged %d lines before Close, want 2", n)
+	}
+
+	if len(w.buffer) != 10 {
+		t.Errorf("buffered %d bytes, want 10", len(w.buffer))
+	}
+
+	w.Close()
+
+	if n := strings.Count(buffer.String(), "\n"); n != 3 {
+		t.Errorf("logged %d lines after Close, want 3", n)
+	}
+}